@@ -2,7 +2,11 @@ package gui
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"runtime"
+	"sync/atomic"
+	"time"
 
 	"domanscy.group/gui/components"
 	"domanscy.group/gui/components/atoms"
@@ -15,15 +19,35 @@ type App struct {
 	title       string
 	rootElement components.Component
 
-	fontStore map[string]rl.Font
-	routine   AppRoutine
+	fontFamilies              map[string]*atoms.FontFamily
+	glyphCache                *atoms.GlyphCache
+	fontFallbacks             map[string][]string
+	fontChains                map[fontChainKey]*atoms.FontChain
+	reportedMissingCodepoints map[fontChainKey]int
+	routine                   AppRoutine
+	lastRecoveredError        error
+	lastRecoveredPhase        int
+	recoveredInFrame          bool
+
+	input    inputSource
+	recorder *eventRecorder
+
+	themeProvider *components.ThemeProvider
+	styleSheet    *components.StyleSheet
+
+	// Files reloaded in dev mode.
+	markups     []*markupFile
+	fontsToLoad []fontToLoad
+
+	frame int
+	time  float64
 
 	windowSize rl.Vector2
 
 	eventBus *atoms.EventBus
 }
 
-func newApp(eventBus *atoms.EventBus, title string, initialSize rl.Vector2, root components.Component, routine AppRoutine) *App {
+func newApp(eventBus *atoms.EventBus, title string, initialSize rl.Vector2, root components.Component, routine AppRoutine, fontFamilies map[string]*atoms.FontFamily, fontFallbacks map[string][]string) *App {
 	// This lock os thread thing protects from crashing in tests when loading fonts.
 	// I don't know exactly why it works, but it works.
 	runtime.LockOSThread()
@@ -31,10 +55,30 @@ func newApp(eventBus *atoms.EventBus, title string, initialSize rl.Vector2, root
 	app := &App{
 		title:       title,
 		rootElement: root,
-		fontStore:   map[string]rl.Font{},
 		routine:     routine,
 		windowSize:  initialSize,
 		eventBus:    eventBus,
+
+		fontFamilies:              fontFamilies,
+		glyphCache:                atoms.NewGlyphCache(),
+		fontFallbacks:             fontFallbacks,
+		fontChains:                map[fontChainKey]*atoms.FontChain{},
+		reportedMissingCodepoints: map[fontChainKey]int{},
+		lastRecoveredError:        nil,
+		lastRecoveredPhase:        ErrorPhaseLayout,
+		recoveredInFrame:          false,
+
+		input:    newLiveInput(),
+		recorder: nil,
+
+		themeProvider: nil,
+		styleSheet:    nil,
+
+		markups:     []*markupFile{},
+		fontsToLoad: []fontToLoad{},
+
+		frame: 0,
+		time:  0,
 	}
 
 	rl.InitWindow(int32(initialSize.X), int32(initialSize.Y), app.title)
@@ -48,62 +92,219 @@ func newApp(eventBus *atoms.EventBus, title string, initialSize rl.Vector2, root
 	return app
 }
 
-func (app *App) run() {
-	app.rootElement.CalculateSize(app.getFont, rl.Vector2{X: float32(rl.GetRenderWidth()), Y: float32(rl.GetRenderHeight())})
+// Post queues the task to be run on the UI thread at the start of the next frame, see atoms.EventBus.InvokeOnUIThread.
+// It is safe to call from any goroutine.
+func (app *App) Post(task func()) {
+	app.eventBus.InvokeOnUIThread(task)
+}
+
+func (app *App) run() error {
+	// The window is closed and the thread unlocked whatever the reason for leaving the loop is.
+	defer func() {
+		// Components release their resources while the window is still open.
+		components.UnmountTree(app.rootElement)
+
+		app.glyphCache.UnloadAll()
+		rl.CloseWindow()
 
-	recalculateOnNextFrame := false
+		// This lock os thread thing protects from crashing in tests when loading fonts.
+		// I don't know exactly why it works, but it works.
+		runtime.UnlockOSThread()
+	}()
 
-	app.eventBus.ListenToEvent("gui:schedule-recalculation", func(args ...interface{}) {
-		recalculateOnNextFrame = true
+	app.windowSize = app.input.initialWindowSize()
+
+	components.MountTree(app.rootElement)
+	app.applyStyleSheet()
+
+	if err := app.calculateLayout(app.windowSize); err != nil {
+		return err
+	}
+
+	if app.recorder != nil {
+		// Frame 0 holds the events of the first layout.
+		app.recorder.beginFrame(0, 0, frameInput{windowSize: app.windowSize})
+
+		if err := app.recorder.endFrame(); err != nil {
+			return err
+		}
+	}
+
+	// Recalculation can be scheduled from any goroutine, as the event bus can be used from all of them.
+	var recalculateOnNextFrame atomic.Bool
+
+	pointer := newPointerStates(app.eventBus, app.rootElement)
+
+	atoms.Subscribe(app.eventBus, components.ScheduleRecalculationEvent, func(struct{}) {
+		recalculateOnNextFrame.Store(true)
 	})
 
 	for !rl.WindowShouldClose() {
-		// window resize event thingies
-		newWindowSize := rlGetWindowSize()
+		// Tasks posted by other goroutines run first, so their changes are laid out and drawn in this frame.
+		app.eventBus.RunUIThreadTasks()
 
-		if !rl.Vector2Equals(newWindowSize, app.windowSize) {
+		input, ok := app.input.nextFrame()
+		if !ok {
+			break
+		}
+
+		app.frame++
+		app.time += float64(input.delta)
+
+		if app.recorder != nil {
+			app.recorder.beginFrame(app.frame, app.time, input)
+		}
+
+		// window resize event thingies
+		if !rl.Vector2Equals(input.windowSize, app.windowSize) {
 			oldWindowSize := app.windowSize
-			app.windowSize = newWindowSize
+			app.windowSize = input.windowSize
 
-			app.eventBus.DispatchEvent("gui:window-resized", WindowResizedEventArgs{
-				oldWindowSize,
-				newWindowSize,
+			atoms.Publish(app.eventBus, WindowResizedEvent, WindowResizedEventArgs{
+				OldWindowSize: oldWindowSize,
+				NewWindowSize: input.windowSize,
 			})
 
-			recalculateOnNextFrame = true
+			recalculateOnNextFrame.Store(true)
 		}
 
-		if recalculateOnNextFrame {
-			app.rootElement.CalculateSize(app.getFont, app.windowSize)
-			recalculateOnNextFrame = false
+		for _, event := range input.events {
+			app.eventBus.DispatchEvent(event.eventType, event.payload)
+		}
+
+		atoms.Publish(app.eventBus, FrameEvent, FrameEventArgs{
+			Frame: app.frame,
+			Delta: input.delta,
+			Time:  app.time,
+		})
+
+		components.CallFrameHooks(app.rootElement, input.delta)
+
+		if recalculateOnNextFrame.Swap(false) {
+			if err := app.calculateLayout(app.windowSize); err != nil {
+				return err
+			}
+		}
+
+		// Pointer states are hit tested against the new layout. The styles of the states they change can change sizes,
+		// so the layout is calculated again in that case, not to draw a frame with the old one.
+		pointer.update()
+		app.applyStyleSheet()
+
+		if recalculateOnNextFrame.Swap(false) {
+			if err := app.calculateLayout(app.windowSize); err != nil {
+				return err
+			}
 		}
 
 		// the rest
 		rl.BeginDrawing()
 
-		rl.ClearBackground(rl.Black)
+		rl.ClearBackground(app.backgroundColor())
+
+		if err := app.rootElement.Render(app.getFont); err != nil && !app.recoverFromError(ErrorPhaseRender, err) {
+			rl.EndDrawing()
+			return err
+		}
 
-		app.rootElement.Render(app.getFont)
+		exitFlag := false
 
 		if app.routine != nil {
-			exitFlag := app.routine()
+			exitFlag = app.routine()
+		}
 
-			rl.EndDrawing()
+		rl.EndDrawing()
+
+		app.glyphCache.NextFrame()
+		app.completeFrame()
 
-			if exitFlag {
-				break
+		if app.recorder != nil {
+			if err := app.recorder.endFrame(); err != nil {
+				return err
 			}
-		} else {
-			rl.EndDrawing()
+		}
+
+		if exitFlag {
+			break
 		}
 	}
 
-	app.unloadAllFonts()
-	rl.CloseWindow()
+	return nil
+}
 
-	// This lock os thread thing protects from crashing in tests when loading fonts.
-	// I don't know exactly why it works, but it works.
-	runtime.UnlockOSThread()
+func (app *App) applyStyleSheet() {
+	if app.styleSheet != nil {
+		app.styleSheet.Apply(app.rootElement)
+	}
+}
+
+// backgroundColor is the background token of the current theme, or black if the app has no theme.
+func (app *App) backgroundColor() rl.Color {
+	if app.themeProvider == nil {
+		return rl.Black
+	}
+
+	color, ok := app.themeProvider.GetTheme().GetColor(components.ColorBackground)
+	if !ok {
+		return rl.Black
+	}
+
+	return color
+}
+
+// calculateLayout returns an error only if it can't be recovered from, see recoverFromError.
+func (app *App) calculateLayout(windowSize rl.Vector2) error {
+	components.CallBeforeLayoutHooks(app.rootElement)
+
+	_, err := app.rootElement.CalculateSize(app.getFont, windowSize)
+	app.reportMissingCodepoints()
+
+	if err == nil {
+		components.CallAfterLayoutHooks(app.rootElement)
+	}
+
+	if err != nil && !app.recoverFromError(ErrorPhaseLayout, err) {
+		return err
+	}
+
+	return nil
+}
+
+// completeFrame forgets the recovered error after a frame without errors,
+// so the next error is dispatched even if it was recovered from before.
+func (app *App) completeFrame() {
+	if !app.recoveredInFrame {
+		app.lastRecoveredError = nil
+	}
+
+	app.recoveredInFrame = false
+}
+
+// recoverFromError dispatches gui:error and reports whether any of the listeners recovered from the error.
+// The same error (see errors.Is) repeated in the same phase frame after frame is dispatched only once and stays recovered.
+func (app *App) recoverFromError(phase int, err error) bool {
+	if app.lastRecoveredError != nil && app.lastRecoveredPhase == phase && errors.Is(err, app.lastRecoveredError) {
+		app.recoveredInFrame = true
+
+		return true
+	}
+
+	args := &ErrorEventArgs{
+		Err:   err,
+		Phase: phase,
+	}
+
+	atoms.Publish(app.eventBus, ErrorEvent, args)
+
+	if !args.recovered {
+		return false
+	}
+
+	app.lastRecoveredError = err
+	app.lastRecoveredPhase = phase
+	app.recoveredInFrame = true
+
+	return true
 }
 
 func rlGetWindowSize() rl.Vector2 {
@@ -115,49 +316,114 @@ func rlGetWindowSize() rl.Vector2 {
 
 var ErrFontDoesNotExist = errors.New("font does not exist")
 
-func (app *App) loadFont(fontName string, fontFilePath string) {
-	if _, err := app.getFont(fontName); err == nil {
-		return
+var ErrNoRootElement = errors.New("root element is not set")
+
+type fontChainKey struct {
+	fontName   string
+	fontWeight int
+	fontStyle  int
+}
+
+func (app *App) getFont(fontName string, fontWeight int, fontStyle int) (*atoms.FontChain, error) {
+	key := fontChainKey{fontName, fontWeight, fontStyle}
+
+	if fontChain, ok := app.fontChains[key]; ok {
+		return fontChain, nil
 	}
 
-	font := rl.LoadFontEx(fontFilePath, 32, nil, 1024)
+	fontSource, err := app.findFontVariant(fontName, fontWeight, fontStyle)
+	if err != nil {
+		return nil, err
+	}
 
-	app.fontStore[fontName] = font
-}
+	fallbackSources := make([]*atoms.FontSource, 0, len(app.fontFallbacks[fontName]))
+
+	// Fallback fonts are picked in the same weight and style.
+	for _, fallbackFontName := range app.fontFallbacks[fontName] {
+		fallbackSource, err := app.findFontVariant(fallbackFontName, fontWeight, fontStyle)
+		if err != nil {
+			return nil, err
+		}
 
-func (app *App) unloadAllFonts() {
-	for _, font := range app.fontStore {
-		rl.UnloadFont(font)
+		fallbackSources = append(fallbackSources, fallbackSource)
 	}
+
+	fontChain := atoms.NewFontChain(app.glyphCache, fontSource, fallbackSources...)
+
+	app.fontChains[key] = fontChain
+
+	return fontChain, nil
 }
 
-func (app *App) getFont(fontName string) (rl.Font, error) {
-	font, ok := app.fontStore[fontName]
+func (app *App) findFontVariant(fontName string, fontWeight int, fontStyle int) (*atoms.FontSource, error) {
+	fontFamily, ok := app.fontFamilies[fontName]
 
 	if !ok {
-		return rl.Font{}, ErrFontDoesNotExist
+		return nil, ErrFontDoesNotExist
+	}
+
+	return fontFamily.FindVariant(fontWeight, fontStyle == components.FontStyleItalic), nil
+}
+
+// reportMissingCodepoints dispatches gui:missing-glyphs for every font chain, which came across new codepoints without a glyph in any of its fonts.
+func (app *App) reportMissingCodepoints() {
+	for key, fontChain := range app.fontChains {
+		missingCodepoints := fontChain.MissingCodepoints()
+
+		if len(missingCodepoints) == app.reportedMissingCodepoints[key] {
+			continue
+		}
+
+		app.reportedMissingCodepoints[key] = len(missingCodepoints)
+
+		atoms.Publish(app.eventBus, MissingGlyphsEvent, MissingGlyphsEventArgs{
+			FontName:   key.fontName,
+			FontWeight: key.fontWeight,
+			FontStyle:  key.fontStyle,
+			Codepoints: missingCodepoints,
+		})
 	}
+}
 
-	return font, nil
+type builderMarkup struct {
+	host *components.LayoutComponent
+	path string
 }
 
 type AppBuilder struct {
 	title       string
 	initialSize rl.Vector2
-	fontsToLoad map[string]string
+	fontsToLoad []fontToLoad
+	fallbacks   map[string][]string
 	rootElement components.Component
 	appRoutine  AppRoutine
 	eventBus    *atoms.EventBus
+
+	themeProvider       *components.ThemeProvider
+	styleSheet          *components.StyleSheet
+	markups             []builderMarkup
+	devMode             bool
+	devModePollInterval time.Duration
+
+	recordingPath    string
+	replayPath       string
+	replayFrameDelta float32
+
+	// err is the first invalid value passed to the builder, it is returned by Run.
+	err error
 }
 
 func BuildApp() *AppBuilder {
 	return &AppBuilder{
 		title:       "",
 		initialSize: rl.Vector2Zero(),
-		fontsToLoad: map[string]string{},
+		fontsToLoad: []fontToLoad{},
+		fallbacks:   map[string][]string{},
 		rootElement: nil,
 		appRoutine:  nil,
 		eventBus:    nil,
+
+		err: nil,
 	}
 }
 
@@ -172,8 +438,52 @@ func (builder *AppBuilder) WithInitialSize(x int, y int) *AppBuilder {
 	return builder
 }
 
-func (builder *AppBuilder) WithFont(fontName string, fontPath string) *AppBuilder {
-	builder.fontsToLoad[fontName] = fontPath
+// WithFont loads a font with normal weight and style, it is used for every weight and style of text with this font name.
+func (builder *AppBuilder) WithFont(fontName string, fontPath string, options ...FontOption) *AppBuilder {
+	return builder.WithFontFile(fontName, FontFromPath(fontPath), options...)
+}
+
+// WithFontFile is WithFont for fonts loaded from other places than the file system, e.g. embedded into the binary.
+func (builder *AppBuilder) WithFontFile(fontName string, fontFile FontFile, options ...FontOption) *AppBuilder {
+	return builder.WithFontVariantFile(fontName, components.FontWeightNormal, components.FontStyleNormal, fontFile, options...)
+}
+
+// WithFontVariant adds a variant of the given weight (100-900) and style to the font family.
+// Text components pick the variant closest to their weight and style.
+func (builder *AppBuilder) WithFontVariant(familyName string, weight int, style int, fontPath string, options ...FontOption) *AppBuilder {
+	return builder.WithFontVariantFile(familyName, weight, style, FontFromPath(fontPath), options...)
+}
+
+func (builder *AppBuilder) WithFontVariantFile(familyName string, weight int, style int, fontFile FontFile, options ...FontOption) *AppBuilder {
+	if weight < components.FontWeightThin || weight > components.FontWeightBlack || weight%100 != 0 {
+		builder.setError(fmt.Errorf("%w: unknown value for font weight: %d", components.ErrInvalidProperty, weight))
+		return builder
+	}
+
+	if style != components.FontStyleNormal && style != components.FontStyleItalic {
+		builder.setError(fmt.Errorf("%w: unknown value for font style: %d", components.ErrInvalidProperty, style))
+		return builder
+	}
+
+	font := fontToLoad{
+		familyName: familyName,
+		weight:     weight,
+		style:      style,
+		file:       fontFile,
+		options:    fontOptions{sdf: false},
+	}
+
+	for _, option := range options {
+		option(&font.options)
+	}
+
+	builder.fontsToLoad = append(builder.fontsToLoad, font)
+	return builder
+}
+
+// WithFontFallbacks sets fonts (loaded with WithFont), which are used in the given order for glyphs missing in the font.
+func (builder *AppBuilder) WithFontFallbacks(fontName string, fallbackFontNames ...string) *AppBuilder {
+	builder.fallbacks[fontName] = fallbackFontNames
 	return builder
 }
 
@@ -192,16 +502,161 @@ func (builder *AppBuilder) WithEventBus(eventBus *atoms.EventBus) *AppBuilder {
 	return builder
 }
 
-func (builder *AppBuilder) Run() {
+// WithTheme sets the theme provider, which components are bound to. The app is cleared with the background color of its current theme.
+// Use components.NewThemeProvider(eventBus, components.DefaultTheme()) for the theme shipped with the library.
+func (builder *AppBuilder) WithTheme(themeProvider *components.ThemeProvider) *AppBuilder {
+	builder.themeProvider = themeProvider
+	return builder
+}
+
+// WithStyleSheet applies the style sheet to the whole tree every frame, so changes of tags and states are styled right away.
+func (builder *AppBuilder) WithStyleSheet(styleSheet *components.StyleSheet) *AppBuilder {
+	builder.styleSheet = styleSheet
+	return builder
+}
+
+// WithMarkup renders the markup file (see components.LoadMarkupFile) into the host layout, when the app starts.
+// The host can be the root element or any layout in the tree.
+func (builder *AppBuilder) WithMarkup(host *components.LayoutComponent, path string) *AppBuilder {
+	builder.markups = append(builder.markups, builderMarkup{host, path})
+	return builder
+}
+
+// WithDevMode watches markup files and fonts added with a path, checking them every pollInterval (DefaultDevModePollInterval if it's 0).
+// Changed markup patches the components of its host and changed fonts are loaded again, without restarting the app,
+// so the window keeps its size and unchanged components keep their state. Every reload dispatches gui:hot-reload.
+func (builder *AppBuilder) WithDevMode(pollInterval time.Duration) *AppBuilder {
+	if pollInterval < 0 {
+		builder.setError(fmt.Errorf("dev mode poll interval can't be negative, got %v", pollInterval))
+		return builder
+	}
+
+	if pollInterval == 0 {
+		pollInterval = DefaultDevModePollInterval
+	}
+
+	builder.devMode = true
+	builder.devModePollInterval = pollInterval
+	return builder
+}
+
+// WithEventRecording writes every event dispatched during the session to the file, one frame per line.
+// The file can be replayed later with WithEventReplay, e.g. to reproduce a bug or in a smoke test.
+func (builder *AppBuilder) WithEventRecording(filePath string) *AppBuilder {
+	builder.recordingPath = filePath
+	return builder
+}
+
+// WithEventReplay feeds the input events and window sizes of a recording to the app, instead of reading them from the window.
+// Every frame takes frameDelta seconds (DefaultReplayFrameDelta if it's 0), and the app stops after the last recorded frame.
+func (builder *AppBuilder) WithEventReplay(filePath string, frameDelta float32) *AppBuilder {
+	if frameDelta < 0 {
+		builder.setError(fmt.Errorf("replay frame delta can't be negative, got %f", frameDelta))
+		return builder
+	}
+
+	if frameDelta == 0 {
+		frameDelta = DefaultReplayFrameDelta
+	}
+
+	builder.replayPath = filePath
+	builder.replayFrameDelta = frameDelta
+	return builder
+}
+
+func (builder *AppBuilder) setError(err error) {
+	if builder.err == nil {
+		builder.err = err
+	}
+}
+
+// Run loads the fonts, opens the window and blocks until it is closed. Fonts are loaded before the window is opened,
+// so if any of them can't be loaded (or a fallback refers to a font which wasn't added), Run returns the error right away.
+// Errors of laying out and rendering components are returned too, unless they are recovered from in the gui:error event.
+func (builder *AppBuilder) Run() error {
+	if builder.err != nil {
+		return builder.err
+	}
+
+	if builder.rootElement == nil {
+		return ErrNoRootElement
+	}
+
 	if builder.eventBus == nil {
 		builder.eventBus = atoms.NewEventBus()
 	}
 
-	app := newApp(builder.eventBus, builder.title, builder.initialSize, builder.rootElement, builder.appRoutine)
+	fontFamilies, err := loadFontFamilies(builder.fontsToLoad)
+	if err != nil {
+		return err
+	}
+
+	for fontName, fallbackFontNames := range builder.fallbacks {
+		for _, fallbackFontName := range fallbackFontNames {
+			if _, ok := fontFamilies[fallbackFontName]; !ok {
+				return fmt.Errorf("fallback font %s of %s: %w", fallbackFontName, fontName, ErrFontDoesNotExist)
+			}
+		}
+	}
+
+	markups := []*markupFile{}
+
+	for _, markup := range builder.markups {
+		markupFile := newMarkupFile(builder.eventBus, markup.host, markup.path)
+
+		if err := markupFile.load(); err != nil {
+			return err
+		}
+
+		markups = append(markups, markupFile)
+	}
+
+	// Files are opened before the window, so errors don't leave it open.
+	var input inputSource = nil
+
+	if builder.replayPath != "" {
+		file, err := os.Open(builder.replayPath)
+		if err != nil {
+			return err
+		}
+
+		input, err = readRecording(file, builder.replayFrameDelta)
+		file.Close()
+
+		if err != nil {
+			return fmt.Errorf("replay of %s: %w", builder.replayPath, err)
+		}
+	}
+
+	var recorder *eventRecorder = nil
+
+	if builder.recordingPath != "" {
+		file, err := os.Create(builder.recordingPath)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		recorder = newEventRecorder(file, builder.eventBus)
+		defer recorder.close()
+	}
+
+	app := newApp(builder.eventBus, builder.title, builder.initialSize, builder.rootElement, builder.appRoutine, fontFamilies, builder.fallbacks)
+	app.markups = markups
+	app.themeProvider = builder.themeProvider
+	app.styleSheet = builder.styleSheet
+	app.fontsToLoad = builder.fontsToLoad
+	app.recorder = recorder
+
+	if input != nil {
+		app.input = input
+	}
 
-	for fontName, fontPath := range builder.fontsToLoad {
-		app.loadFont(fontName, fontPath)
+	if builder.devMode {
+		stopHotReload := app.startHotReload(builder.devModePollInterval)
+		defer stopHotReload()
 	}
 
-	app.run()
+	return app.run()
 }
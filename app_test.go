@@ -0,0 +1,82 @@
+package gui
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+)
+
+func TestRecoverFromError(t *testing.T) {
+	app := &App{eventBus: atoms.NewEventBus()}
+
+	dispatched := []error{}
+	atoms.Subscribe(app.eventBus, ErrorEvent, func(errorArgs *ErrorEventArgs) {
+		dispatched = append(dispatched, errorArgs.Err)
+		errorArgs.Recover()
+	})
+
+	fontError := errors.New("font not loaded")
+
+	if !app.recoverFromError(ErrorPhaseLayout, fontError) || !app.recoverFromError(ErrorPhaseLayout, fontError) {
+		t.Fatalf("Expected the error to be recovered from")
+	}
+
+	if len(dispatched) != 1 {
+		t.Errorf("Expected the repeated error to be dispatched once, received: %v", dispatched)
+	}
+
+	app.recoverFromError(ErrorPhaseRender, fontError)
+
+	if len(dispatched) != 2 {
+		t.Errorf("Expected the error to be dispatched again in another phase, received: %v", dispatched)
+	}
+
+	app.recoverFromError(ErrorPhaseRender, errors.New("font not loaded"))
+
+	if len(dispatched) != 3 {
+		t.Errorf("Expected a different error with the same message to be dispatched, received: %v", dispatched)
+	}
+
+	app.completeFrame()
+	app.recoverFromError(ErrorPhaseRender, fontError)
+	app.completeFrame()
+	app.completeFrame()
+	app.recoverFromError(ErrorPhaseRender, fontError)
+
+	if len(dispatched) != 5 {
+		t.Errorf("Expected the error to be dispatched again after a frame without errors, received: %v", dispatched)
+	}
+}
+
+func TestPost(t *testing.T) {
+	app := &App{eventBus: atoms.NewEventBus()}
+
+	tasks := 0
+	waitGroup := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			app.Post(func() {
+				tasks++
+			})
+		}()
+	}
+
+	waitGroup.Wait()
+
+	if tasks != 0 {
+		t.Errorf("Expected the tasks to wait for the UI thread, %d of them already ran", tasks)
+	}
+
+	app.eventBus.RunUIThreadTasks()
+
+	if tasks != 10 {
+		t.Errorf("Expected all tasks to run on the UI thread, received: %d", tasks)
+	}
+}
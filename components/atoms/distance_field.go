@@ -0,0 +1,351 @@
+package atoms
+
+import (
+	"math"
+	"unsafe"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Glyphs of SDF fonts are rasterized once, at this size, and scaled to any font size by the distance field shader.
+const sdfGlyphSize = 64
+
+// The distance field reaches this many pixels (at sdfGlyphSize) outside and inside of every glyph,
+// it limits how wide outlines and glows can be.
+const sdfSpread = 12
+
+const sdfAtlasPadding = 2
+
+const sdfFragmentShader = `#version 330
+
+in vec2 fragTexCoord;
+in vec4 fragColor;
+
+uniform sampler2D texture0;
+uniform vec4 colDiffuse;
+
+uniform float distancePerPixel;
+uniform float outlineWidth;
+uniform vec4 outlineColor;
+uniform float glowRadius;
+uniform vec4 glowColor;
+
+out vec4 finalColor;
+
+vec4 blendOver(vec4 top, vec4 bottom)
+{
+    float alpha = top.a + bottom.a*(1.0 - top.a);
+
+    if (alpha == 0.0) return vec4(0.0);
+
+    return vec4((top.rgb*top.a + bottom.rgb*bottom.a*(1.0 - top.a))/alpha, alpha);
+}
+
+void main()
+{
+    // 0.5 is the edge of the glyph, bigger values are inside of it.
+    float distance = texture(texture0, fragTexCoord).a;
+    float smoothing = 0.7*fwidth(distance);
+
+    float outlineEdge = 0.5 - outlineWidth*distancePerPixel;
+    float glowEdge = outlineEdge - glowRadius*distancePerPixel;
+
+    vec4 fill = fragColor*colDiffuse;
+    fill.a *= smoothstep(0.5 - smoothing, 0.5 + smoothing, distance);
+
+    vec4 outline = outlineColor;
+    outline.a *= smoothstep(outlineEdge - smoothing, outlineEdge + smoothing, distance);
+
+    vec4 glow = glowColor;
+    glow.a *= smoothstep(glowEdge, outlineEdge, distance);
+
+    finalColor = blendOver(blendOver(fill, outline), glow);
+}
+`
+
+// TextEffects are drawn around glyphs of SDF fonts, they are ignored by bitmap fonts.
+type TextEffects struct {
+	OutlineWidth float32
+	OutlineColor rl.Color
+	GlowRadius   float32
+	GlowColor    rl.Color
+}
+
+type sdfShader struct {
+	shader rl.Shader
+
+	distancePerPixelLocation int32
+	outlineWidthLocation     int32
+	outlineColorLocation     int32
+	glowRadiusLocation       int32
+	glowColorLocation        int32
+}
+
+func loadSDFShader() *sdfShader {
+	// An empty vertex shader means raylib's default one.
+	shader := rl.LoadShaderFromMemory("", sdfFragmentShader)
+
+	return &sdfShader{
+		shader: shader,
+
+		distancePerPixelLocation: rl.GetShaderLocation(shader, "distancePerPixel"),
+		outlineWidthLocation:     rl.GetShaderLocation(shader, "outlineWidth"),
+		outlineColorLocation:     rl.GetShaderLocation(shader, "outlineColor"),
+		glowRadiusLocation:       rl.GetShaderLocation(shader, "glowRadius"),
+		glowColorLocation:        rl.GetShaderLocation(shader, "glowColor"),
+	}
+}
+
+// clampTextEffects narrows the outline and the glow, so together they fit in the distance the field reaches outside of glyphs drawn at fontSize.
+func clampTextEffects(fontSize float32, effects TextEffects) TextEffects {
+	maxWidth := sdfSpread * fontSize / sdfGlyphSize
+
+	effects.OutlineWidth = min(effects.OutlineWidth, maxWidth)
+	effects.GlowRadius = min(effects.GlowRadius, maxWidth-effects.OutlineWidth)
+
+	return effects
+}
+
+func (shader *sdfShader) begin(fontSize float32, effects TextEffects) {
+	effects = clampTextEffects(fontSize, effects)
+
+	// A pixel on the screen covers sdfGlyphSize/fontSize pixels of the field, and the field goes from 0.5 to 0 over sdfSpread pixels.
+	distancePerPixel := sdfGlyphSize / fontSize * 0.5 / sdfSpread
+
+	outlineColor := rl.ColorNormalize(effects.OutlineColor)
+	glowColor := rl.ColorNormalize(effects.GlowColor)
+
+	rl.SetShaderValue(shader.shader, shader.distancePerPixelLocation, []float32{distancePerPixel}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader.shader, shader.outlineWidthLocation, []float32{effects.OutlineWidth}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader.shader, shader.outlineColorLocation, []float32{outlineColor.X, outlineColor.Y, outlineColor.Z, outlineColor.W}, rl.ShaderUniformVec4)
+	rl.SetShaderValue(shader.shader, shader.glowRadiusLocation, []float32{effects.GlowRadius}, rl.ShaderUniformFloat)
+	rl.SetShaderValue(shader.shader, shader.glowColorLocation, []float32{glowColor.X, glowColor.Y, glowColor.Z, glowColor.W}, rl.ShaderUniformVec4)
+
+	rl.BeginShaderMode(shader.shader)
+}
+
+// loadSDFFont rasterizes the codepoints into a distance field atlas. The returned font references only Go memory
+// and its texture, so it has to be unloaded with rl.UnloadTexture instead of rl.UnloadFont.
+// It returns ErrUnsupportedFontFile and an empty font, if raylib can't read any glyphs from the font data.
+func loadSDFFont(source *FontSource, codepoints []rune) (rl.Font, error) {
+	glyphs := rl.LoadFontData(source.data, sdfGlyphSize, codepoints, rl.FontDefault)
+	if len(glyphs) == 0 {
+		return rl.Font{}, ErrUnsupportedFontFile
+	}
+
+	fields := make([][]byte, len(glyphs))
+	fieldSizes := make([]rl.Vector2, len(glyphs))
+	sdfGlyphs := make([]rl.GlyphInfo, len(glyphs))
+
+	for i, glyph := range glyphs {
+		width := int(glyph.Image.Width)
+		height := int(glyph.Image.Height)
+
+		var bitmap []byte
+
+		if glyph.Image.Data != nil && width > 0 && height > 0 {
+			bitmap = unsafe.Slice((*byte)(glyph.Image.Data), width*height)
+		} else {
+			width, height = 0, 0
+		}
+
+		field, fieldWidth, fieldHeight := generateDistanceField(bitmap, width, height, sdfSpread)
+
+		fields[i] = field
+		fieldSizes[i] = rl.Vector2{X: float32(fieldWidth), Y: float32(fieldHeight)}
+		sdfGlyphs[i] = rl.GlyphInfo{
+			Value:    glyph.Value,
+			OffsetX:  glyph.OffsetX - sdfSpread,
+			OffsetY:  glyph.OffsetY - sdfSpread,
+			AdvanceX: glyph.AdvanceX,
+		}
+	}
+
+	rl.UnloadFontData(glyphs)
+
+	recs, atlasWidth, atlasHeight := packGlyphs(fieldSizes, sdfAtlasPadding)
+
+	// The field goes to the alpha channel, so the atlas can be drawn with raylib's text functions.
+	pixels := make([]byte, atlasWidth*atlasHeight*4)
+
+	for i, field := range fields {
+		rec := recs[i]
+
+		for y := 0; y < int(rec.Height); y++ {
+			for x := 0; x < int(rec.Width); x++ {
+				pixel := ((int(rec.Y)+y)*atlasWidth + int(rec.X) + x) * 4
+
+				pixels[pixel] = 255
+				pixels[pixel+1] = 255
+				pixels[pixel+2] = 255
+				pixels[pixel+3] = field[y*int(rec.Width)+x]
+			}
+		}
+	}
+
+	texture := rl.LoadTextureFromImage(rl.NewImage(pixels, int32(atlasWidth), int32(atlasHeight), 1, rl.UncompressedR8g8b8a8))
+	rl.SetTextureFilter(texture, rl.FilterBilinear)
+
+	return rl.Font{
+		BaseSize:     sdfGlyphSize,
+		CharsCount:   int32(len(sdfGlyphs)),
+		CharsPadding: 0,
+		Texture:      texture,
+		Recs:         &recs[0],
+		Chars:        &sdfGlyphs[0],
+	}, nil
+}
+
+// packGlyphs places rectangles of the given sizes in rows of an atlas, which is roughly square.
+func packGlyphs(sizes []rl.Vector2, padding int) (recs []rl.Rectangle, atlasWidth int, atlasHeight int) {
+	var area float32
+
+	for _, size := range sizes {
+		area += (size.X + float32(padding)) * (size.Y + float32(padding))
+	}
+
+	atlasWidth = 64
+
+	for float32(atlasWidth*atlasWidth) < area*1.2 {
+		atlasWidth *= 2
+	}
+
+	recs = make([]rl.Rectangle, len(sizes))
+
+	x, y, rowHeight := padding, padding, 0
+
+	for i, size := range sizes {
+		width := int(size.X)
+		height := int(size.Y)
+
+		for x+width+padding > atlasWidth && x > padding {
+			x = padding
+			y += rowHeight + padding
+			rowHeight = 0
+		}
+
+		// A single glyph wider than the atlas widens it.
+		for x+width+padding > atlasWidth {
+			atlasWidth *= 2
+		}
+
+		recs[i] = rl.Rectangle{X: float32(x), Y: float32(y), Width: size.X, Height: size.Y}
+
+		x += width + padding
+		rowHeight = max(rowHeight, height)
+	}
+
+	atlasHeight = y + rowHeight + padding
+
+	return
+}
+
+// generateDistanceField turns a grayscale glyph bitmap into a signed distance field, which has spread pixels of margin on every side.
+// Every pixel of the field stores the distance to the edge of the glyph, 128 is the edge, 255 is spread pixels inside and 0 spread pixels outside.
+func generateDistanceField(bitmap []byte, width int, height int, spread int) (field []byte, fieldWidth int, fieldHeight int) {
+	fieldWidth = width + spread*2
+	fieldHeight = height + spread*2
+
+	inside := make([]bool, fieldWidth*fieldHeight)
+	outside := make([]bool, fieldWidth*fieldHeight)
+
+	for i := range outside {
+		outside[i] = true
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			index := (y+spread)*fieldWidth + x + spread
+
+			inside[index] = bitmap[y*width+x] >= 128
+			outside[index] = !inside[index]
+		}
+	}
+
+	distancesToInside := findNearestDistances(inside, fieldWidth, fieldHeight)
+	distancesToOutside := findNearestDistances(outside, fieldWidth, fieldHeight)
+
+	field = make([]byte, fieldWidth*fieldHeight)
+
+	for i := range field {
+		// The edge lies halfway between the centers of an inside and an outside pixel.
+		var distance float64
+
+		if inside[i] {
+			distance = distancesToOutside[i] - 0.5
+		} else {
+			distance = -(distancesToInside[i] - 0.5)
+		}
+
+		value := 127.5 + distance/float64(spread)*127.5
+
+		field[i] = byte(math.Round(math.Max(0, math.Min(255, value))))
+	}
+
+	return
+}
+
+// findNearestDistances returns, for every pixel, the distance to the nearest seed pixel,
+// using the 8-point sequential Euclidean distance transform (8SSEDT).
+func findNearestDistances(seeds []bool, width int, height int) []float64 {
+	type offset struct{ dx, dy int }
+
+	const far = 1 << 14
+
+	offsets := make([]offset, len(seeds))
+
+	for i, seed := range seeds {
+		if !seed {
+			offsets[i] = offset{far, far}
+		}
+	}
+
+	compare := func(x int, y int, dx int, dy int) {
+		if x+dx < 0 || x+dx >= width || y+dy < 0 || y+dy >= height {
+			return
+		}
+
+		current := &offsets[y*width+x]
+		neighbour := offsets[(y+dy)*width+x+dx]
+		neighbour.dx += dx
+		neighbour.dy += dy
+
+		if neighbour.dx*neighbour.dx+neighbour.dy*neighbour.dy < current.dx*current.dx+current.dy*current.dy {
+			*current = neighbour
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			compare(x, y, -1, 0)
+			compare(x, y, 0, -1)
+			compare(x, y, -1, -1)
+			compare(x, y, 1, -1)
+		}
+
+		for x := width - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+		}
+	}
+
+	for y := height - 1; y >= 0; y-- {
+		for x := width - 1; x >= 0; x-- {
+			compare(x, y, 1, 0)
+			compare(x, y, 0, 1)
+			compare(x, y, -1, 1)
+			compare(x, y, 1, 1)
+		}
+
+		for x := 0; x < width; x++ {
+			compare(x, y, -1, 0)
+		}
+	}
+
+	distances := make([]float64, len(seeds))
+
+	for i, offset := range offsets {
+		distances[i] = math.Sqrt(float64(offset.dx*offset.dx + offset.dy*offset.dy))
+	}
+
+	return distances
+}
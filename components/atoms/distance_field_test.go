@@ -0,0 +1,102 @@
+package atoms
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestGenerateDistanceField(t *testing.T) {
+	// A 4x4 square in the middle of an 8x8 bitmap.
+	bitmap := make([]byte, 8*8)
+
+	for y := 2; y < 6; y++ {
+		for x := 2; x < 6; x++ {
+			bitmap[y*8+x] = 255
+		}
+	}
+
+	field, fieldWidth, fieldHeight := generateDistanceField(bitmap, 8, 8, 4)
+
+	if fieldWidth != 16 || fieldHeight != 16 {
+		t.Fatalf("Expected a 16x16 field, received: %dx%d", fieldWidth, fieldHeight)
+	}
+
+	valueAt := func(x int, y int) byte {
+		return field[(y+4)*fieldWidth+x+4]
+	}
+
+	t.Run("Pixels inside of the glyph are above the edge value", func(t *testing.T) {
+		if valueAt(2, 2) <= 128 || valueAt(3, 3) <= valueAt(2, 2) {
+			t.Errorf("Expected values growing towards the center, received: %d, %d", valueAt(2, 2), valueAt(3, 3))
+		}
+	})
+
+	t.Run("Pixels outside of the glyph are below the edge value", func(t *testing.T) {
+		if valueAt(1, 3) >= 128 || valueAt(0, 3) >= valueAt(1, 3) {
+			t.Errorf("Expected values falling away from the glyph, received: %d, %d", valueAt(1, 3), valueAt(0, 3))
+		}
+	})
+
+	t.Run("The field fades out after the spread", func(t *testing.T) {
+		if field[0] != 0 {
+			t.Errorf("Expected 0 in the corner of the field, received: %d", field[0])
+		}
+	})
+
+	t.Run("Empty glyph", func(t *testing.T) {
+		field, fieldWidth, fieldHeight := generateDistanceField(nil, 0, 0, 4)
+
+		if fieldWidth != 8 || fieldHeight != 8 || field[0] != 0 {
+			t.Errorf("Expected an empty 8x8 field, received: %dx%d", fieldWidth, fieldHeight)
+		}
+	})
+}
+
+func TestPackGlyphs(t *testing.T) {
+	sizes := []rl.Vector2{{X: 30, Y: 40}, {X: 20, Y: 10}, {X: 50, Y: 20}, {X: 200, Y: 10}}
+
+	recs, atlasWidth, atlasHeight := packGlyphs(sizes, 2)
+
+	for i, rec := range recs {
+		if rec.Width != sizes[i].X || rec.Height != sizes[i].Y {
+			t.Errorf("Expected rectangle %d to have the size of its glyph, received: %v", i, rec)
+		}
+
+		if rec.X < 2 || rec.Y < 2 || int(rec.X+rec.Width)+2 > atlasWidth || int(rec.Y+rec.Height)+2 > atlasHeight {
+			t.Errorf("Expected rectangle %d to fit in the %dx%d atlas, received: %v", i, atlasWidth, atlasHeight, rec)
+		}
+
+		for j := 0; j < i; j++ {
+			other := recs[j]
+
+			if rec.X < other.X+other.Width && other.X < rec.X+rec.Width && rec.Y < other.Y+other.Height && other.Y < rec.Y+rec.Height {
+				t.Errorf("Expected rectangles %d and %d not to overlap: %v, %v", i, j, rec, other)
+			}
+		}
+	}
+}
+
+func TestClampTextEffects(t *testing.T) {
+	// At 64 pixels the field reaches 12 pixels outside of glyphs.
+	testCases := []struct {
+		name                 string
+		effects              TextEffects
+		expectedOutlineWidth float32
+		expectedGlowRadius   float32
+	}{
+		{"Effects within the field", TextEffects{OutlineWidth: 4, GlowRadius: 6}, 4, 6},
+		{"Wide glow", TextEffects{OutlineWidth: 4, GlowRadius: 20}, 4, 8},
+		{"Wide outline", TextEffects{OutlineWidth: 30, GlowRadius: 5}, 12, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			effects := clampTextEffects(64, testCase.effects)
+
+			if effects.OutlineWidth != testCase.expectedOutlineWidth || effects.GlowRadius != testCase.expectedGlowRadius {
+				t.Errorf("Expected outline %f and glow %f, received: %f and %f", testCase.expectedOutlineWidth, testCase.expectedGlowRadius, effects.OutlineWidth, effects.GlowRadius)
+			}
+		})
+	}
+}
@@ -1,47 +1,247 @@
 package atoms
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
 
 type ChangeWindowSizeEventCallback = func(oldWindowSize rl.Vector2, newWindowSize rl.Vector2)
 
 type EventCallback = func(...interface{})
 
+// Listeners with higher priority are called first, this is the default one.
+const DefaultListenerPriority = 0
+
+// UnsubscribeFunc removes the listener it was returned for, calling it more than once does nothing.
+type UnsubscribeFunc = func()
+
+// EventTapCallback observes every dispatched event, see EventBus.Tap.
+type EventTapCallback = func(eventType string, args ...interface{})
+
+type eventListener struct {
+	callback EventCallback
+	priority int
+	once     bool
+	removed  atomic.Bool
+
+	// eventType is the event type or pattern the listener was registered for.
+	eventType string
+	// sequence orders listeners registered for different event types and patterns, when they match the same event.
+	sequence uint64
+}
+
+type eventTap struct {
+	callback EventTapCallback
+	removed  atomic.Bool
+}
+
+// EventBus can be used from many goroutines at once. Callbacks are called on the goroutine which dispatches the event,
+// so code running outside of the UI thread should update components through InvokeOnUIThread.
+// Listeners are called by priority, and in the order they were registered if their priorities are equal.
 type EventBus struct {
-	callbacks map[string]map[int]EventCallback
-	nextId    int
+	mutex     sync.RWMutex
+	listeners map[string][]*eventListener
+	patterns  map[string][]string
+	taps      []*eventTap
+	sequence  uint64
+
+	uiThreadMutex sync.Mutex
+	uiThreadTasks []func()
 }
 
 func NewEventBus() *EventBus {
 	instance := &EventBus{
-		callbacks: map[string]map[int]EventCallback{},
-		nextId:    1,
+		listeners: map[string][]*eventListener{},
+		patterns:  map[string][]string{},
+		taps:      []*eventTap{},
+		sequence:  0,
+
+		uiThreadTasks: []func(){},
 	}
 
 	return instance
 }
 
-func (evStore *EventBus) ListenToEvent(eventType string, callback EventCallback) (eventId int) {
-	eventId = evStore.nextId
+// ListenToEvent registers a callback of the event type. The event type can also be a pattern of colon separated segments,
+// where * matches a single segment and ** any number of them, e.g. gui:* or app:user:**.
+func (evStore *EventBus) ListenToEvent(eventType string, callback EventCallback) UnsubscribeFunc {
+	return evStore.listen(eventType, DefaultListenerPriority, false, callback)
+}
+
+func (evStore *EventBus) ListenToEventWithPriority(eventType string, priority int, callback EventCallback) UnsubscribeFunc {
+	return evStore.listen(eventType, priority, false, callback)
+}
+
+// ListenOnce registers a callback which is removed right before it is called for the first time.
+func (evStore *EventBus) ListenOnce(eventType string, callback EventCallback) UnsubscribeFunc {
+	return evStore.listen(eventType, DefaultListenerPriority, true, callback)
+}
+
+func (evStore *EventBus) listen(eventType string, priority int, once bool, callback EventCallback) UnsubscribeFunc {
+	evStore.mutex.Lock()
+	defer evStore.mutex.Unlock()
+
+	evStore.sequence++
+
+	listener := &eventListener{
+		callback:  callback,
+		priority:  priority,
+		once:      once,
+		eventType: eventType,
+		sequence:  evStore.sequence,
+	}
+
+	listeners := evStore.listeners[eventType]
+
+	// The listener goes after all listeners with the same or higher priority.
+	position := len(listeners)
+
+	for i, other := range listeners {
+		if other.priority < priority {
+			position = i
+			break
+		}
+	}
+
+	// A new slice is created every time, so dispatches in progress keep iterating over their own copy.
+	evStore.listeners[eventType] = slices.Insert(slices.Clip(listeners), position, listener)
+
+	if isEventPattern(eventType) {
+		evStore.patterns[eventType] = strings.Split(eventType, ":")
+	}
+
+	return func() {
+		evStore.removeListener(listener)
+	}
+}
+
+func (evStore *EventBus) removeListener(listener *eventListener) {
+	listener.removed.Store(true)
 
-	if _, ok := evStore.callbacks[eventType]; !ok {
-		evStore.callbacks[eventType] = map[int]EventCallback{}
+	evStore.mutex.Lock()
+	defer evStore.mutex.Unlock()
+
+	listeners := evStore.listeners[listener.eventType]
+
+	if i := slices.Index(listeners, listener); i != -1 {
+		evStore.listeners[listener.eventType] = slices.Delete(slices.Clone(listeners), i, i+1)
+	}
+
+	if len(evStore.listeners[listener.eventType]) == 0 {
+		delete(evStore.listeners, listener.eventType)
+		delete(evStore.patterns, listener.eventType)
 	}
+}
+
+// Tap registers a callback, which observes every dispatched event (before its listeners), e.g. for logging and debugging tools.
+func (evStore *EventBus) Tap(callback EventTapCallback) UnsubscribeFunc {
+	evStore.mutex.Lock()
+	defer evStore.mutex.Unlock()
+
+	tap := &eventTap{callback: callback}
+
+	evStore.taps = append(slices.Clip(evStore.taps), tap)
 
-	evStore.callbacks[eventType][eventId] = callback
+	return func() {
+		tap.removed.Store(true)
 
-	evStore.nextId += 1
+		evStore.mutex.Lock()
+		defer evStore.mutex.Unlock()
 
-	return
+		if i := slices.Index(evStore.taps, tap); i != -1 {
+			evStore.taps = slices.Delete(slices.Clone(evStore.taps), i, i+1)
+		}
+	}
 }
 
 func (evStore *EventBus) DispatchEvent(eventType string, args ...interface{}) {
-	for _, callback := range evStore.callbacks[eventType] {
-		callback(args...)
+	// Callbacks are called without holding the lock, so they can register and remove listeners themselves.
+	evStore.mutex.RLock()
+	taps := evStore.taps
+	listeners := evStore.findListeners(eventType)
+	evStore.mutex.RUnlock()
+
+	for _, tap := range taps {
+		if !tap.removed.Load() {
+			tap.callback(eventType, args...)
+		}
+	}
+
+	for _, listener := range listeners {
+		// Listeners removed by the callbacks called earlier in this dispatch are skipped.
+		if listener.removed.Load() {
+			continue
+		}
+
+		if listener.once {
+			// Only one dispatch can win a once-listener, even if they run concurrently.
+			if !listener.removed.CompareAndSwap(false, true) {
+				continue
+			}
+
+			evStore.removeListener(listener)
+		}
+
+		listener.callback(args...)
 	}
 }
 
-func (evStore *EventBus) RemoveRegisteredEvent(eventId int) {
-	for eventType := range evStore.callbacks {
-		delete(evStore.callbacks[eventType], eventId)
+// findListeners returns listeners of the event type and of all patterns matching it, it has to be called with the lock held.
+func (evStore *EventBus) findListeners(eventType string) []*eventListener {
+	listeners := evStore.listeners[eventType]
+
+	if len(evStore.patterns) == 0 {
+		return listeners
+	}
+
+	eventSegments := strings.Split(eventType, ":")
+	merged := false
+
+	for pattern, patternSegments := range evStore.patterns {
+		if pattern == eventType || !matchEventPattern(patternSegments, eventSegments) {
+			continue
+		}
+
+		listeners = append(slices.Clip(listeners), evStore.listeners[pattern]...)
+		merged = true
+	}
+
+	if merged {
+		slices.SortFunc(listeners, func(a *eventListener, b *eventListener) int {
+			if a.priority != b.priority {
+				return cmp.Compare(b.priority, a.priority)
+			}
+
+			return cmp.Compare(a.sequence, b.sequence)
+		})
+	}
+
+	return listeners
+}
+
+// InvokeOnUIThread queues the task to be run by the app on its (UI) thread, at the start of the next frame.
+// It is safe to call from any goroutine, e.g. after a network request or a file change.
+func (evStore *EventBus) InvokeOnUIThread(task func()) {
+	evStore.uiThreadMutex.Lock()
+	defer evStore.uiThreadMutex.Unlock()
+
+	evStore.uiThreadTasks = append(evStore.uiThreadTasks, task)
+}
+
+// RunUIThreadTasks runs the queued tasks in the order they were queued. Tasks queued by these tasks wait for the next call.
+// It is called by the app once per frame and shouldn't be called from other goroutines.
+func (evStore *EventBus) RunUIThreadTasks() {
+	evStore.uiThreadMutex.Lock()
+	tasks := evStore.uiThreadTasks
+	evStore.uiThreadTasks = []func(){}
+	evStore.uiThreadMutex.Unlock()
+
+	for _, task := range tasks {
+		task()
 	}
 }
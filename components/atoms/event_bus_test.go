@@ -0,0 +1,261 @@
+package atoms
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEventBusConcurrency(t *testing.T) {
+	eventBus := NewEventBus()
+
+	var mutex sync.Mutex
+	calls := 0
+
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < 100; j++ {
+				unsubscribe := eventBus.ListenToEvent("test:event", func(args ...interface{}) {
+					mutex.Lock()
+					calls++
+					mutex.Unlock()
+				})
+
+				eventBus.DispatchEvent("test:event", j)
+				unsubscribe()
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	// Every dispatch reaches at least the listener registered right before it.
+	if calls < 800 {
+		t.Errorf("Expected at least 800 calls, received: %d", calls)
+	}
+}
+
+func TestEventBusOrdering(t *testing.T) {
+	t.Run("Registration order and priorities", func(t *testing.T) {
+		eventBus := NewEventBus()
+		order := []string{}
+
+		listen := func(name string, priority int) {
+			eventBus.ListenToEventWithPriority("test:event", priority, func(args ...interface{}) {
+				order = append(order, name)
+			})
+		}
+
+		listen("first", DefaultListenerPriority)
+		listen("second", DefaultListenerPriority)
+		listen("urgent", 10)
+		listen("late", -10)
+		listen("third", DefaultListenerPriority)
+		listen("urgent second", 10)
+
+		eventBus.DispatchEvent("test:event")
+
+		expectedOrder := []string{"urgent", "urgent second", "first", "second", "third", "late"}
+
+		if !slices.Equal(order, expectedOrder) {
+			t.Errorf("Expected %v, received: %v", expectedOrder, order)
+		}
+	})
+
+	t.Run("Listen once", func(t *testing.T) {
+		eventBus := NewEventBus()
+		calls := 0
+
+		eventBus.ListenOnce("test:event", func(args ...interface{}) {
+			calls++
+			eventBus.DispatchEvent("test:event")
+		})
+
+		eventBus.DispatchEvent("test:event")
+		eventBus.DispatchEvent("test:event")
+
+		if calls != 1 {
+			t.Errorf("Expected 1 call, received: %d", calls)
+		}
+	})
+
+	t.Run("Removal during dispatch", func(t *testing.T) {
+		eventBus := NewEventBus()
+		order := []string{}
+
+		var unsubscribeFirst, unsubscribeSecond UnsubscribeFunc
+
+		unsubscribeFirst = eventBus.ListenToEvent("test:event", func(args ...interface{}) {
+			order = append(order, "first")
+			unsubscribeFirst()
+			unsubscribeSecond()
+		})
+
+		unsubscribeSecond = eventBus.ListenToEvent("test:event", func(args ...interface{}) {
+			order = append(order, "second")
+		})
+
+		eventBus.ListenToEvent("test:event", func(args ...interface{}) {
+			order = append(order, "third")
+		})
+
+		eventBus.DispatchEvent("test:event")
+		eventBus.DispatchEvent("test:event")
+
+		expectedOrder := []string{"first", "third", "third"}
+
+		if !slices.Equal(order, expectedOrder) {
+			t.Errorf("Expected %v, received: %v", expectedOrder, order)
+		}
+
+		// Unsubscribing again does nothing.
+		unsubscribeFirst()
+	})
+}
+
+func TestInvokeOnUIThread(t *testing.T) {
+	eventBus := NewEventBus()
+
+	results := []int{}
+
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			// The tasks modify results without any locking, as they all run on the thread calling RunUIThreadTasks.
+			eventBus.InvokeOnUIThread(func() {
+				results = append(results, i)
+			})
+		}(i)
+	}
+
+	waitGroup.Wait()
+
+	t.Run("Queued tasks run on RunUIThreadTasks", func(t *testing.T) {
+		if len(results) != 0 {
+			t.Fatalf("Expected no task to run before RunUIThreadTasks, %d did", len(results))
+		}
+
+		eventBus.RunUIThreadTasks()
+
+		if len(results) != 8 {
+			t.Errorf("Expected 8 tasks to run, %d did", len(results))
+		}
+	})
+
+	t.Run("Tasks queued by tasks wait for the next run", func(t *testing.T) {
+		order := []string{}
+
+		eventBus.InvokeOnUIThread(func() {
+			order = append(order, "first")
+
+			eventBus.InvokeOnUIThread(func() {
+				order = append(order, "nested")
+			})
+		})
+
+		eventBus.InvokeOnUIThread(func() {
+			order = append(order, "second")
+		})
+
+		eventBus.RunUIThreadTasks()
+
+		if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+			t.Errorf("Expected [first second], received: %v", order)
+		}
+
+		eventBus.RunUIThreadTasks()
+
+		if len(order) != 3 || order[2] != "nested" {
+			t.Errorf("Expected the nested task to run on the second call, received: %v", order)
+		}
+	})
+}
+
+func TestEventPatterns(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		event    string
+		expected bool
+	}{
+		{"gui:*", "gui:window-resized", true},
+		{"gui:*", "gui:a:b", false},
+		{"gui:*", "gui", false},
+		{"gui:*", "app:window-resized", false},
+		{"app:user:**", "app:user:login", true},
+		{"app:user:**", "app:user:profile:changed", true},
+		{"app:user:**", "app:user", true},
+		{"app:user:**", "app:users:login", false},
+		{"**:changed", "app:user:profile:changed", true},
+		{"*:*:login", "app:user:login", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%s matching %s", testCase.pattern, testCase.event), func(t *testing.T) {
+			matched := matchEventPattern(strings.Split(testCase.pattern, ":"), strings.Split(testCase.event, ":"))
+
+			if matched != testCase.expected {
+				t.Errorf("Expected %t, received: %t", testCase.expected, matched)
+			}
+		})
+	}
+
+	t.Run("Pattern and exact listeners keep the registration order", func(t *testing.T) {
+		eventBus := NewEventBus()
+		order := []string{}
+
+		eventBus.ListenToEvent("gui:window-resized", func(args ...interface{}) {
+			order = append(order, "exact")
+		})
+
+		unsubscribe := eventBus.ListenToEvent("gui:*", func(args ...interface{}) {
+			order = append(order, "pattern")
+		})
+
+		eventBus.ListenToEventWithPriority("**", 1, func(args ...interface{}) {
+			order = append(order, "everything")
+		})
+
+		eventBus.DispatchEvent("gui:window-resized")
+		unsubscribe()
+		eventBus.DispatchEvent("gui:window-resized")
+
+		expectedOrder := []string{"everything", "exact", "pattern", "everything", "exact"}
+
+		if !slices.Equal(order, expectedOrder) {
+			t.Errorf("Expected %v, received: %v", expectedOrder, order)
+		}
+	})
+
+	t.Run("Taps observe every event", func(t *testing.T) {
+		eventBus := NewEventBus()
+		tapped := []string{}
+
+		unsubscribe := eventBus.Tap(func(eventType string, args ...interface{}) {
+			tapped = append(tapped, fmt.Sprint(eventType, args))
+		})
+
+		eventBus.DispatchEvent("gui:window-resized", 1)
+		eventBus.DispatchEvent("app:user:login")
+		unsubscribe()
+		eventBus.DispatchEvent("app:user:logout")
+
+		expectedTapped := []string{"gui:window-resized[1]", "app:user:login[]"}
+
+		if !slices.Equal(tapped, expectedTapped) {
+			t.Errorf("Expected %v, received: %v", expectedTapped, tapped)
+		}
+	})
+}
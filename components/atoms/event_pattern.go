@@ -0,0 +1,36 @@
+package atoms
+
+import (
+	"slices"
+	"strings"
+)
+
+// isEventPattern reports whether the event type contains * or ** segments.
+func isEventPattern(eventType string) bool {
+	return slices.ContainsFunc(strings.Split(eventType, ":"), func(segment string) bool {
+		return segment == "*" || segment == "**"
+	})
+}
+
+// matchEventPattern matches segments of an event type against a pattern, where * matches exactly one segment
+// and ** matches any number of segments, including none.
+func matchEventPattern(pattern []string, eventType []string) bool {
+	if len(pattern) == 0 {
+		return len(eventType) == 0
+	}
+
+	switch pattern[0] {
+	case "**":
+		for skipped := 0; skipped <= len(eventType); skipped++ {
+			if matchEventPattern(pattern[1:], eventType[skipped:]) {
+				return true
+			}
+		}
+
+		return false
+	case "*":
+		return len(eventType) > 0 && matchEventPattern(pattern[1:], eventType[1:])
+	default:
+		return len(eventType) > 0 && pattern[0] == eventType[0] && matchEventPattern(pattern[1:], eventType[1:])
+	}
+}
@@ -10,28 +10,30 @@ type Font interface {
 }
 
 type RaylibFont struct {
-	font     rl.Font
-	fontSize float32
-	spacing  float32
+	fontChain *FontChain
+	fontSize  float32
+	spacing   float32
 }
 
-func NewRaylibFont(font rl.Font, fontSize float32, spacing float32) *RaylibFont {
+func NewRaylibFont(fontChain *FontChain, fontSize float32, spacing float32) *RaylibFont {
 	return &RaylibFont{
-		font,
+		fontChain,
 		fontSize,
 		spacing,
 	}
 }
 
 func (font RaylibFont) GlyphWidth(codepoint rune) float32 {
-	glyphInfo := rl.GetGlyphInfo(font.font, codepoint)
+	glyphFont := font.fontChain.GetGlyphFont(codepoint, font.fontSize)
 
-	fontScalingFactor := float32(font.font.BaseSize) / font.fontSize
+	glyphInfo := rl.GetGlyphInfo(glyphFont, codepoint)
+
+	fontScalingFactor := font.fontSize / float32(glyphFont.BaseSize)
 
 	if glyphInfo.AdvanceX != 0 {
 		return float32(glyphInfo.AdvanceX) * fontScalingFactor
 	} else {
-		return (font.font.Recs.Width + float32(glyphInfo.OffsetX)) * fontScalingFactor
+		return (rl.GetGlyphAtlasRec(glyphFont, codepoint).Width + float32(glyphInfo.OffsetX)) * fontScalingFactor
 	}
 }
 
@@ -0,0 +1,66 @@
+package atoms
+
+import (
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FontChain is a font with an ordered list of fallback fonts. Every codepoint is measured and drawn
+// with the first font of the chain which contains its glyph, e.g. Latin, then CJK, then emoji.
+type FontChain struct {
+	glyphCache        *GlyphCache
+	sources           []*FontSource
+	missingCodepoints map[rune]bool
+}
+
+func NewFontChain(glyphCache *GlyphCache, primarySource *FontSource, fallbackSources ...*FontSource) *FontChain {
+	return &FontChain{
+		glyphCache:        glyphCache,
+		sources:           append([]*FontSource{primarySource}, fallbackSources...),
+		missingCodepoints: map[rune]bool{},
+	}
+}
+
+// FindSource returns the first font source of the chain which contains the codepoint. If none of them does,
+// the primary one is returned (so the missing glyph is drawn the way raylib does it) and the codepoint is reported as missing.
+func (chain *FontChain) FindSource(codepoint rune) (source *FontSource, found bool) {
+	for _, source := range chain.sources {
+		if source.HasGlyph(codepoint) {
+			return source, true
+		}
+	}
+
+	chain.missingCodepoints[codepoint] = true
+
+	return chain.sources[0], false
+}
+
+// GetGlyphFont returns a raylib font with the glyph of the codepoint rasterized for the given font size.
+func (chain *FontChain) GetGlyphFont(codepoint rune, fontSize float32) rl.Font {
+	source, _ := chain.FindSource(codepoint)
+
+	return chain.glyphCache.GetGlyphFont(source, fontSize, codepoint)
+}
+
+// BeginSDFMode starts drawing glyphs of SDF fonts, for details see GlyphCache.BeginSDFMode.
+func (chain *FontChain) BeginSDFMode(fontSize float32, effects TextEffects) {
+	chain.glyphCache.BeginSDFMode(fontSize, effects)
+}
+
+func (chain *FontChain) EndSDFMode() {
+	chain.glyphCache.EndSDFMode()
+}
+
+// MissingCodepoints returns all codepoints which were requested so far, but have no glyph in any font of the chain.
+func (chain *FontChain) MissingCodepoints() []rune {
+	missingCodepoints := make([]rune, 0, len(chain.missingCodepoints))
+
+	for codepoint := range chain.missingCodepoints {
+		missingCodepoints = append(missingCodepoints, codepoint)
+	}
+
+	slices.Sort(missingCodepoints)
+
+	return missingCodepoints
+}
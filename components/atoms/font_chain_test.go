@@ -0,0 +1,73 @@
+package atoms
+
+import (
+	"slices"
+	"testing"
+)
+
+func newTestFontSource(codepoints string) *FontSource {
+	coverage := map[rune]bool{}
+
+	for _, codepoint := range codepoints {
+		coverage[codepoint] = true
+	}
+
+	return &FontSource{coverage: coverage}
+}
+
+func TestFontChainFindSource(t *testing.T) {
+	latin := newTestFontSource("abc")
+	cjk := newTestFontSource("日本a")
+	emoji := newTestFontSource("😀")
+
+	chain := NewFontChain(nil, latin, cjk, emoji)
+
+	testCases := []struct {
+		codepoint      rune
+		expectedSource *FontSource
+		expectedFound  bool
+	}{
+		{'a', latin, true},
+		{'日', cjk, true},
+		{'😀', emoji, true},
+		{'ż', latin, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.codepoint), func(t *testing.T) {
+			source, found := chain.FindSource(testCase.codepoint)
+
+			if source != testCase.expectedSource {
+				t.Errorf("Expected source %p, received: %p", testCase.expectedSource, source)
+			}
+
+			if found != testCase.expectedFound {
+				t.Errorf("Expected found to be %t", testCase.expectedFound)
+			}
+		})
+	}
+
+	t.Run("Sources without a character map contain every glyph", func(t *testing.T) {
+		unknown := &FontSource{}
+
+		if source, found := NewFontChain(nil, latin, unknown).FindSource('ż'); source != unknown || !found {
+			t.Errorf("Expected the source without a character map, received: %p", source)
+		}
+	})
+}
+
+func TestFontChainMissingCodepoints(t *testing.T) {
+	chain := NewFontChain(nil, newTestFontSource("abc"))
+
+	if missing := chain.MissingCodepoints(); len(missing) != 0 {
+		t.Errorf("Expected no missing codepoints, received: %q", missing)
+	}
+
+	for _, codepoint := range "zaxzb" {
+		chain.FindSource(codepoint)
+	}
+
+	if missing := chain.MissingCodepoints(); !slices.Equal(missing, []rune{'x', 'z'}) {
+		t.Errorf("Expected sorted missing codepoints without duplicates, received: %q", missing)
+	}
+}
@@ -0,0 +1,100 @@
+package atoms
+
+import "slices"
+
+type fontVariant struct {
+	weight int
+	italic bool
+}
+
+// FontFamily groups font sources of the same typeface, which differ by weight (100-900) and style.
+type FontFamily struct {
+	variants map[fontVariant]*FontSource
+}
+
+func NewFontFamily() *FontFamily {
+	return &FontFamily{
+		variants: map[fontVariant]*FontSource{},
+	}
+}
+
+// AddVariant adds the variant or replaces the source of an existing one.
+func (family *FontFamily) AddVariant(weight int, italic bool, source *FontSource) {
+	family.variants[fontVariant{weight, italic}] = source
+}
+
+// GetVariant returns the source of the exact variant, unlike FindVariant.
+func (family *FontFamily) GetVariant(weight int, italic bool) (*FontSource, bool) {
+	source, ok := family.variants[fontVariant{weight, italic}]
+	return source, ok
+}
+
+// FindVariant returns the font source of the variant closest to the requested one, following the CSS font matching algorithm:
+// https://www.w3.org/TR/css-fonts-4/#font-style-matching
+// Style is matched first, so an italic variant of any weight is preferred over a normal one of the exact weight.
+// It returns nil only if the family has no variants.
+func (family *FontFamily) FindVariant(weight int, italic bool) *FontSource {
+	for _, style := range []bool{italic, !italic} {
+		weights := []int{}
+
+		for variant := range family.variants {
+			if variant.italic == style {
+				weights = append(weights, variant.weight)
+			}
+		}
+
+		if len(weights) > 0 {
+			return family.variants[fontVariant{findNearestWeight(weights, weight), style}]
+		}
+	}
+
+	return nil
+}
+
+// findNearestWeight picks one of the available weights, the same way as browsers do it: for weights between 400 and 500
+// the ones up to 500 are checked first, then lighter ones, then heavier ones. Lighter requested weights prefer lighter available weights
+// and heavier requested weights prefer heavier available weights.
+func findNearestWeight(weights []int, weight int) int {
+	if slices.Contains(weights, weight) {
+		return weight
+	}
+
+	slices.Sort(weights)
+
+	lighter := func() (int, bool) {
+		for i := len(weights) - 1; i >= 0; i-- {
+			if weights[i] < weight {
+				return weights[i], true
+			}
+		}
+
+		return 0, false
+	}
+
+	heavier := func(limit int) (int, bool) {
+		for _, available := range weights {
+			if available > weight && available <= limit {
+				return available, true
+			}
+		}
+
+		return 0, false
+	}
+
+	searchOrder := []func() (int, bool){lighter, func() (int, bool) { return heavier(1000) }}
+
+	switch {
+	case weight >= 400 && weight <= 500:
+		searchOrder = []func() (int, bool){func() (int, bool) { return heavier(500) }, lighter, func() (int, bool) { return heavier(1000) }}
+	case weight > 500:
+		searchOrder = []func() (int, bool){func() (int, bool) { return heavier(1000) }, lighter}
+	}
+
+	for _, search := range searchOrder {
+		if found, ok := search(); ok {
+			return found
+		}
+	}
+
+	return weights[0]
+}
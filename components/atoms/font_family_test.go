@@ -0,0 +1,72 @@
+package atoms
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindNearestWeight(t *testing.T) {
+	testCases := []struct {
+		weights        []int
+		weight         int
+		expectedWeight int
+	}{
+		{[]int{400, 700}, 700, 700},
+		{[]int{300, 500, 700}, 400, 500},
+		{[]int{300, 600}, 400, 300},
+		{[]int{600, 700}, 400, 600},
+		{[]int{300, 400, 700}, 500, 400},
+		{[]int{100, 300, 900}, 200, 100},
+		{[]int{300, 900}, 200, 300},
+		{[]int{400, 800, 900}, 600, 800},
+		{[]int{300, 400}, 800, 400},
+		{[]int{400}, 100, 400},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d from %v", testCase.weight, testCase.weights), func(t *testing.T) {
+			if weight := findNearestWeight(testCase.weights, testCase.weight); weight != testCase.expectedWeight {
+				t.Errorf("Expected weight %d, received: %d", testCase.expectedWeight, weight)
+			}
+		})
+	}
+}
+
+func TestFontFamilyFindVariant(t *testing.T) {
+	regular := &FontSource{}
+	bold := &FontSource{}
+	italic := &FontSource{}
+
+	family := NewFontFamily()
+
+	if family.FindVariant(400, false) != nil {
+		t.Errorf("Expected no variant in an empty family")
+	}
+
+	family.AddVariant(400, false, regular)
+	family.AddVariant(700, false, bold)
+
+	t.Run("Missing italic falls back to the normal style", func(t *testing.T) {
+		if family.FindVariant(700, true) != bold {
+			t.Errorf("Expected the bold variant")
+		}
+	})
+
+	family.AddVariant(400, true, italic)
+
+	t.Run("Style is matched before weight", func(t *testing.T) {
+		if family.FindVariant(700, true) != italic {
+			t.Errorf("Expected the italic variant")
+		}
+	})
+
+	t.Run("Nearest weight", func(t *testing.T) {
+		if family.FindVariant(600, false) != bold {
+			t.Errorf("Expected the bold variant")
+		}
+
+		if family.FindVariant(300, false) != regular {
+			t.Errorf("Expected the regular variant")
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package atoms
+
+import (
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// FontSource is a font file kept in memory, so its glyphs can be rasterized when they are needed for the first time.
+type FontSource struct {
+	fileType string
+	data     []byte
+	coverage map[rune]bool
+	sdf      bool
+}
+
+// NewFontSource creates a font source from the content of a TrueType or OpenType font file.
+// It returns ErrUnsupportedFontFile if the data isn't one of them.
+func NewFontSource(data []byte) (*FontSource, error) {
+	fileType, err := detectFontFileType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the character map can't be read, coverage stays nil and every codepoint is assumed to be in the font.
+	coverage, _ := readCodepointCoverage(data)
+
+	return &FontSource{
+		fileType: fileType,
+		data:     data,
+		coverage: coverage,
+		sdf:      false,
+	}, nil
+}
+
+// NewSDFFontSource creates a font source, which glyphs are rendered as signed distance fields,
+// so they stay crisp at any size and can be drawn with TextEffects.
+func NewSDFFontSource(data []byte) (*FontSource, error) {
+	source, err := NewFontSource(data)
+	if err != nil {
+		return nil, err
+	}
+
+	source.sdf = true
+
+	return source, nil
+}
+
+func (source *FontSource) IsSDF() bool {
+	return source.sdf
+}
+
+func (source *FontSource) HasGlyph(codepoint rune) bool {
+	if source.coverage == nil {
+		return true
+	}
+
+	return source.coverage[codepoint]
+}
+
+// Glyphs are rasterized at one of these sizes (the smallest one not less than the requested size) and scaled down when drawn.
+var glyphSizeBuckets = []int32{8, 12, 16, 20, 24, 32, 48, 64, 96, 128, 192, 256}
+
+// Codepoints are rasterized in aligned blocks of this size, so the neighbours of a codepoint (usually from the same script)
+// don't need another rasterization.
+const glyphBlockSize = 32
+
+const glyphPageCapacity = 256
+
+// Pages not used for this many frames are unloaded from the GPU memory.
+const glyphPageLifetimeInFrames = 600
+
+func getGlyphSizeBucket(fontSize float32) int32 {
+	for _, bucket := range glyphSizeBuckets {
+		if float32(bucket) >= fontSize {
+			return bucket
+		}
+	}
+
+	// Above the largest bucket sizes are rounded up to a multiple of 64.
+	return (int32(fontSize) + 63) / 64 * 64
+}
+
+type glyphPageKey struct {
+	source *FontSource
+	size   int32
+}
+
+// glyphPage is a texture atlas with glyphs of a single font source rasterized at a single size.
+type glyphPage struct {
+	font          rl.Font
+	codepoints    []rune
+	lastUsedFrame int
+}
+
+// retiredGlyphFont is an atlas of a page which grew. It's unloaded in the next frame,
+// because fonts returned for the page before are still drawn in this one.
+type retiredGlyphFont struct {
+	key  glyphPageKey
+	font rl.Font
+}
+
+type glyphPageSet struct {
+	pages            []*glyphPage
+	pageOfCodepoints map[rune]*glyphPage
+}
+
+// GlyphCache rasterizes glyphs lazily, at the size they are drawn with. Glyphs are packed into pages,
+// which grow until they are full and are evicted when they are not used for a while.
+type GlyphCache struct {
+	pageSets     map[glyphPageKey]*glyphPageSet
+	retiredFonts []retiredGlyphFont
+	frame        int
+	sdfShader    *sdfShader
+}
+
+func NewGlyphCache() *GlyphCache {
+	return &GlyphCache{
+		pageSets:     map[glyphPageKey]*glyphPageSet{},
+		retiredFonts: []retiredGlyphFont{},
+		frame:        0,
+		sdfShader:    nil,
+	}
+}
+
+// GetGlyphFont returns a raylib font containing the glyph of the codepoint, rasterizing it if needed.
+// The base size of the returned font may be bigger than fontSize, so it has to be scaled when used.
+func (cache *GlyphCache) GetGlyphFont(source *FontSource, fontSize float32, codepoint rune) rl.Font {
+	key := glyphPageKey{source, getGlyphSizeBucket(fontSize)}
+
+	if source.sdf {
+		key.size = sdfGlyphSize
+	}
+
+	pageSet, ok := cache.pageSets[key]
+
+	if !ok {
+		pageSet = &glyphPageSet{
+			pages:            []*glyphPage{},
+			pageOfCodepoints: map[rune]*glyphPage{},
+		}
+
+		cache.pageSets[key] = pageSet
+	}
+
+	page, ok := pageSet.pageOfCodepoints[codepoint]
+
+	if !ok {
+		page = cache.rasterize(key, pageSet, codepoint)
+	}
+
+	page.lastUsedFrame = cache.frame
+
+	return page.font
+}
+
+func (cache *GlyphCache) rasterize(key glyphPageKey, pageSet *glyphPageSet, codepoint rune) *glyphPage {
+	codepoints := []rune{codepoint}
+
+	blockStart := codepoint - codepoint%glyphBlockSize
+
+	for blockCodepoint := blockStart; blockCodepoint < blockStart+glyphBlockSize; blockCodepoint++ {
+		if _, ok := pageSet.pageOfCodepoints[blockCodepoint]; !ok && blockCodepoint != codepoint && key.source.coverage[blockCodepoint] {
+			codepoints = append(codepoints, blockCodepoint)
+		}
+	}
+
+	var page *glyphPage
+
+	if len(pageSet.pages) > 0 && len(pageSet.pages[len(pageSet.pages)-1].codepoints)+len(codepoints) <= glyphPageCapacity {
+		// The page grows, so its atlas is generated again with the new glyphs.
+		page = pageSet.pages[len(pageSet.pages)-1]
+		cache.retiredFonts = append(cache.retiredFonts, retiredGlyphFont{key, page.font})
+	} else {
+		page = &glyphPage{}
+		pageSet.pages = append(pageSet.pages, page)
+	}
+
+	page.codepoints = append(page.codepoints, codepoints...)
+
+	if key.source.sdf {
+		// A font which can't be loaded stays empty, raylib draws it with its default font, the same way as a broken bitmap font.
+		page.font, _ = loadSDFFont(key.source, page.codepoints)
+	} else {
+		page.font = rl.LoadFontFromMemory(key.source.fileType, key.source.data, key.size, page.codepoints)
+	}
+
+	for _, pageCodepoint := range codepoints {
+		pageSet.pageOfCodepoints[pageCodepoint] = page
+	}
+
+	return page
+}
+
+func unloadGlyphFont(key glyphPageKey, font rl.Font) {
+	if key.source.sdf {
+		// The glyphs and rectangles of SDF fonts live in Go memory, only the texture belongs to raylib.
+		rl.UnloadTexture(font.Texture)
+	} else {
+		rl.UnloadFont(font)
+	}
+}
+
+func (cache *GlyphCache) unloadRetiredFonts() {
+	for _, retired := range cache.retiredFonts {
+		unloadGlyphFont(retired.key, retired.font)
+	}
+
+	cache.retiredFonts = []retiredGlyphFont{}
+}
+
+// BeginSDFMode starts drawing with the distance field shader, glyphs of SDF fonts have to be drawn between BeginSDFMode and EndSDFMode.
+func (cache *GlyphCache) BeginSDFMode(fontSize float32, effects TextEffects) {
+	// The shader can't be loaded before the window is created, so it is loaded when it is needed for the first time.
+	if cache.sdfShader == nil {
+		cache.sdfShader = loadSDFShader()
+	}
+
+	cache.sdfShader.begin(fontSize, effects)
+}
+
+func (cache *GlyphCache) EndSDFMode() {
+	rl.EndShaderMode()
+}
+
+// NextFrame has to be called once per frame, it unloads the pages which weren't used recently
+// and the atlases replaced during the previous frame.
+func (cache *GlyphCache) NextFrame() {
+	cache.frame++
+
+	cache.unloadRetiredFonts()
+
+	for key, pageSet := range cache.pageSets {
+		pageSet.pages = slices.DeleteFunc(pageSet.pages, func(page *glyphPage) bool {
+			if cache.frame-page.lastUsedFrame < glyphPageLifetimeInFrames {
+				return false
+			}
+
+			unloadGlyphFont(key, page.font)
+
+			for _, codepoint := range page.codepoints {
+				delete(pageSet.pageOfCodepoints, codepoint)
+			}
+
+			return true
+		})
+
+		if len(pageSet.pages) == 0 {
+			delete(cache.pageSets, key)
+		}
+	}
+}
+
+// UnloadSource unloads all pages of the font source, e.g. when it's replaced by a newer version of the font file.
+func (cache *GlyphCache) UnloadSource(source *FontSource) {
+	for key, pageSet := range cache.pageSets {
+		if key.source != source {
+			continue
+		}
+
+		for _, page := range pageSet.pages {
+			unloadGlyphFont(key, page.font)
+		}
+
+		delete(cache.pageSets, key)
+	}
+
+	cache.retiredFonts = slices.DeleteFunc(cache.retiredFonts, func(retired retiredGlyphFont) bool {
+		if retired.key.source != source {
+			return false
+		}
+
+		unloadGlyphFont(retired.key, retired.font)
+
+		return true
+	})
+}
+
+func (cache *GlyphCache) UnloadAll() {
+	for key, pageSet := range cache.pageSets {
+		for _, page := range pageSet.pages {
+			unloadGlyphFont(key, page.font)
+		}
+	}
+
+	cache.pageSets = map[glyphPageKey]*glyphPageSet{}
+	cache.unloadRetiredFonts()
+
+	if cache.sdfShader != nil {
+		rl.UnloadShader(cache.sdfShader.shader)
+		cache.sdfShader = nil
+	}
+}
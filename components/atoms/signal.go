@@ -0,0 +1,238 @@
+package atoms
+
+import "slices"
+
+// Signals hold state which components can be bound to. Computed values and effects track the signals they read,
+// and are updated whenever one of them changes.
+// Signals aren't safe for concurrent use, other goroutines should update them through EventBus.InvokeOnUIThread.
+
+// ReadonlySignal is implemented by Signal and Computed.
+type ReadonlySignal[T comparable] interface {
+	// Get returns the value and, when called by a computed value or an effect, makes it depend on the signal.
+	Get() T
+	// Peek returns the value without tracking it.
+	Peek() T
+}
+
+// observer is a computed value or an effect.
+type observer interface {
+	invalidate()
+	addSource(source *observable)
+}
+
+// observable keeps track of the observers of a signal or a computed value.
+type observable struct {
+	observers []observer
+}
+
+// currentObserver is the computed value or effect, which is being evaluated right now.
+var currentObserver observer
+
+var batchDepth = 0
+var pendingEffects = []*Effect{}
+
+func (source *observable) track() {
+	if currentObserver == nil || slices.Contains(source.observers, currentObserver) {
+		return
+	}
+
+	source.observers = append(source.observers, currentObserver)
+	currentObserver.addSource(source)
+}
+
+func (source *observable) removeObserver(target observer) {
+	source.observers = slices.DeleteFunc(slices.Clone(source.observers), func(other observer) bool {
+		return other == target
+	})
+}
+
+func (source *observable) notify() {
+	for _, target := range slices.Clone(source.observers) {
+		target.invalidate()
+	}
+}
+
+// tracking evaluates the function as the observer, which then depends only on the signals read this time.
+func tracking(target observer, sources *[]*observable, evaluate func()) {
+	for _, source := range *sources {
+		source.removeObserver(target)
+	}
+
+	*sources = []*observable{}
+
+	previousObserver := currentObserver
+	currentObserver = target
+
+	defer func() {
+		currentObserver = previousObserver
+	}()
+
+	evaluate()
+}
+
+// Batch delays effects until the function returns, so signals updated together run every effect only once.
+func Batch(update func()) {
+	batchDepth++
+
+	defer func() {
+		batchDepth--
+		runPendingEffects()
+	}()
+
+	update()
+}
+
+func runPendingEffects() {
+	if batchDepth > 0 {
+		return
+	}
+
+	for len(pendingEffects) > 0 {
+		effect := pendingEffects[0]
+		pendingEffects = pendingEffects[1:]
+
+		effect.run()
+	}
+}
+
+type Signal[T comparable] struct {
+	observable
+
+	value T
+}
+
+func NewSignal[T comparable](value T) *Signal[T] {
+	return &Signal[T]{
+		observable: observable{observers: []observer{}},
+		value:      value,
+	}
+}
+
+func (signal *Signal[T]) Get() T {
+	signal.track()
+	return signal.value
+}
+
+func (signal *Signal[T]) Peek() T {
+	return signal.value
+}
+
+// Set changes the value and runs the dependent effects, unless the value is equal to the current one.
+func (signal *Signal[T]) Set(value T) {
+	if signal.value == value {
+		return
+	}
+
+	signal.value = value
+
+	Batch(signal.notify)
+}
+
+func (signal *Signal[T]) Update(update func(value T) T) {
+	signal.Set(update(signal.value))
+}
+
+// Computed is a value derived from other signals. It's calculated lazily, the first time it's read after one of them changes.
+type Computed[T comparable] struct {
+	observable
+
+	calculate func() T
+	value     T
+	dirty     bool
+	sources   []*observable
+}
+
+func NewComputed[T comparable](calculate func() T) *Computed[T] {
+	return &Computed[T]{
+		observable: observable{observers: []observer{}},
+		calculate:  calculate,
+		dirty:      true,
+		sources:    []*observable{},
+	}
+}
+
+func (computed *Computed[T]) Get() T {
+	computed.track()
+	return computed.Peek()
+}
+
+func (computed *Computed[T]) Peek() T {
+	if computed.dirty {
+		tracking(computed, &computed.sources, func() {
+			computed.value = computed.calculate()
+		})
+
+		computed.dirty = false
+	}
+
+	return computed.value
+}
+
+func (computed *Computed[T]) invalidate() {
+	if computed.dirty {
+		return
+	}
+
+	computed.dirty = true
+	computed.notify()
+}
+
+func (computed *Computed[T]) addSource(source *observable) {
+	computed.sources = append(computed.sources, source)
+}
+
+// Effect runs a function whenever one of the signals it read during the last run changes.
+type Effect struct {
+	callback func()
+	sources  []*observable
+	pending  bool
+	disposed bool
+}
+
+// NewEffect runs the callback right away and then after every change of its dependencies, until it's disposed.
+func NewEffect(callback func()) *Effect {
+	effect := &Effect{
+		callback: callback,
+		sources:  []*observable{},
+		pending:  false,
+		disposed: false,
+	}
+
+	effect.run()
+
+	return effect
+}
+
+func (effect *Effect) run() {
+	effect.pending = false
+
+	if effect.disposed {
+		return
+	}
+
+	tracking(effect, &effect.sources, effect.callback)
+}
+
+func (effect *Effect) invalidate() {
+	if effect.pending || effect.disposed {
+		return
+	}
+
+	effect.pending = true
+	pendingEffects = append(pendingEffects, effect)
+}
+
+func (effect *Effect) addSource(source *observable) {
+	effect.sources = append(effect.sources, source)
+}
+
+// Dispose stops the effect, it has the signature of UnsubscribeFunc, so it can be released with other listeners.
+func (effect *Effect) Dispose() {
+	effect.disposed = true
+
+	for _, source := range effect.sources {
+		source.removeObserver(effect)
+	}
+
+	effect.sources = []*observable{}
+}
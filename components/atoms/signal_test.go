@@ -0,0 +1,102 @@
+package atoms
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSignals(t *testing.T) {
+	t.Run("Effects run after every change", func(t *testing.T) {
+		count := NewSignal(1)
+		seen := []int{}
+
+		effect := NewEffect(func() {
+			seen = append(seen, count.Get())
+		})
+
+		count.Set(2)
+		count.Set(2)
+		count.Update(func(value int) int { return value * 10 })
+
+		effect.Dispose()
+		count.Set(30)
+
+		if !slices.Equal(seen, []int{1, 2, 20}) {
+			t.Errorf("Expected effect runs [1 2 20], got %v", seen)
+		}
+	})
+
+	t.Run("Computed values are lazy and cached", func(t *testing.T) {
+		firstName := NewSignal("Ada")
+		lastName := NewSignal("Lovelace")
+		calculations := 0
+
+		fullName := NewComputed(func() string {
+			calculations++
+			return firstName.Get() + " " + lastName.Get()
+		})
+
+		if calculations != 0 {
+			t.Errorf("Expected no calculation before the first read, got %d", calculations)
+		}
+
+		if fullName.Get() != "Ada Lovelace" || fullName.Get() != "Ada Lovelace" || calculations != 1 {
+			t.Errorf("Expected a single calculation of the full name, got %d", calculations)
+		}
+
+		lastName.Set("Byron")
+
+		if fullName.Peek() != "Ada Byron" || calculations != 2 {
+			t.Errorf("Expected the full name to be recalculated once, got %q after %d calculations", fullName.Peek(), calculations)
+		}
+	})
+
+	t.Run("Effects depend on computed values and only on signals read in the last run", func(t *testing.T) {
+		useFallback := NewSignal(false)
+		name := NewSignal("Ada")
+		fallback := NewSignal("Anonymous")
+
+		greeting := NewComputed(func() string {
+			if useFallback.Get() {
+				return "Hello " + fallback.Get()
+			}
+
+			return "Hello " + name.Get()
+		})
+
+		seen := []string{}
+		NewEffect(func() {
+			seen = append(seen, greeting.Get())
+		})
+
+		fallback.Set("Nobody")
+		useFallback.Set(true)
+		name.Set("Grace")
+		fallback.Set("Someone")
+
+		expected := []string{"Hello Ada", "Hello Nobody", "Hello Someone"}
+		if !slices.Equal(seen, expected) {
+			t.Errorf("Expected %v, got %v", expected, seen)
+		}
+	})
+
+	t.Run("Batched changes run effects once", func(t *testing.T) {
+		width := NewSignal(1)
+		height := NewSignal(1)
+		runs := 0
+
+		NewEffect(func() {
+			_ = width.Get() * height.Get()
+			runs++
+		})
+
+		Batch(func() {
+			width.Set(10)
+			height.Set(20)
+		})
+
+		if runs != 2 {
+			t.Errorf("Expected 2 runs of the effect, got %d", runs)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package atoms
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrUnsupportedFontFile = errors.New("unsupported font file")
+
+// detectFontFileType checks the signature of a font file and returns its extension, as expected by raylib.
+func detectFontFileType(fontData []byte) (string, error) {
+	if len(fontData) < 4 {
+		return "", ErrUnsupportedFontFile
+	}
+
+	switch string(fontData[0:4]) {
+	case "\x00\x01\x00\x00", "true", "ttcf":
+		return ".ttf", nil
+	case "OTTO":
+		return ".otf", nil
+	}
+
+	return "", ErrUnsupportedFontFile
+}
+
+// readCodepointCoverage reads the character to glyph mapping (cmap table) of a TrueType or OpenType font
+// and returns all codepoints which have a glyph in the font. Only the most common formats, 4 and 12, are supported.
+// https://learn.microsoft.com/en-us/typography/opentype/spec/cmap
+func readCodepointCoverage(fontData []byte) (map[rune]bool, error) {
+	cmap, err := findFontTable(fontData, "cmap")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cmap) < 4 {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	subtableCount := int(binary.BigEndian.Uint16(cmap[2:4]))
+
+	var bestSubtable []byte
+	bestSubtableFormat := 0
+
+	for i := 0; i < subtableCount; i++ {
+		recordOffset := 4 + i*8
+
+		if recordOffset+8 > len(cmap) {
+			return nil, ErrUnsupportedFontFile
+		}
+
+		platformId := binary.BigEndian.Uint16(cmap[recordOffset : recordOffset+2])
+		subtableOffset := int(binary.BigEndian.Uint32(cmap[recordOffset+4 : recordOffset+8]))
+
+		// Unicode and Windows platforms, the others use legacy encodings.
+		if platformId != 0 && platformId != 3 {
+			continue
+		}
+
+		if subtableOffset+2 > len(cmap) {
+			return nil, ErrUnsupportedFontFile
+		}
+
+		subtable := cmap[subtableOffset:]
+		format := int(binary.BigEndian.Uint16(subtable[0:2]))
+
+		// Format 12 covers the whole Unicode range, so it is preferred over format 4, which covers only the BMP.
+		if (format == 4 && bestSubtableFormat == 0) || format == 12 {
+			bestSubtable = subtable
+			bestSubtableFormat = format
+		}
+	}
+
+	switch bestSubtableFormat {
+	case 4:
+		return readCmapFormat4(bestSubtable)
+	case 12:
+		return readCmapFormat12(bestSubtable)
+	default:
+		return nil, ErrUnsupportedFontFile
+	}
+}
+
+func findFontTable(fontData []byte, tag string) ([]byte, error) {
+	if len(fontData) < 12 {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	tableCount := int(binary.BigEndian.Uint16(fontData[4:6]))
+
+	for i := 0; i < tableCount; i++ {
+		recordOffset := 12 + i*16
+
+		if recordOffset+16 > len(fontData) {
+			return nil, ErrUnsupportedFontFile
+		}
+
+		if string(fontData[recordOffset:recordOffset+4]) != tag {
+			continue
+		}
+
+		tableOffset := int(binary.BigEndian.Uint32(fontData[recordOffset+8 : recordOffset+12]))
+		tableLength := int(binary.BigEndian.Uint32(fontData[recordOffset+12 : recordOffset+16]))
+
+		if tableOffset+tableLength > len(fontData) {
+			return nil, ErrUnsupportedFontFile
+		}
+
+		return fontData[tableOffset : tableOffset+tableLength], nil
+	}
+
+	return nil, ErrUnsupportedFontFile
+}
+
+func readCmapFormat4(subtable []byte) (map[rune]bool, error) {
+	if len(subtable) < 14 {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	segmentCount := int(binary.BigEndian.Uint16(subtable[6:8])) / 2
+
+	endCodesOffset := 14
+	startCodesOffset := endCodesOffset + segmentCount*2 + 2
+	idDeltasOffset := startCodesOffset + segmentCount*2
+	idRangeOffsetsOffset := idDeltasOffset + segmentCount*2
+
+	if idRangeOffsetsOffset+segmentCount*2 > len(subtable) {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	readUint16 := func(offset int) uint16 {
+		return binary.BigEndian.Uint16(subtable[offset : offset+2])
+	}
+
+	coverage := map[rune]bool{}
+
+	for segment := 0; segment < segmentCount; segment++ {
+		endCode := int(readUint16(endCodesOffset + segment*2))
+		startCode := int(readUint16(startCodesOffset + segment*2))
+		idDelta := int(readUint16(idDeltasOffset + segment*2))
+		idRangeOffsetPosition := idRangeOffsetsOffset + segment*2
+		idRangeOffset := int(readUint16(idRangeOffsetPosition))
+
+		for codepoint := startCode; codepoint <= endCode && codepoint != 0xFFFF; codepoint++ {
+			glyphIndex := 0
+
+			if idRangeOffset == 0 {
+				glyphIndex = (codepoint + idDelta) & 0xFFFF
+			} else {
+				glyphIndexOffset := idRangeOffsetPosition + idRangeOffset + (codepoint-startCode)*2
+
+				if glyphIndexOffset+2 > len(subtable) {
+					return nil, ErrUnsupportedFontFile
+				}
+
+				if glyphIndex = int(readUint16(glyphIndexOffset)); glyphIndex != 0 {
+					glyphIndex = (glyphIndex + idDelta) & 0xFFFF
+				}
+			}
+
+			if glyphIndex != 0 {
+				coverage[rune(codepoint)] = true
+			}
+		}
+	}
+
+	return coverage, nil
+}
+
+func readCmapFormat12(subtable []byte) (map[rune]bool, error) {
+	if len(subtable) < 16 {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	groupCount := int(binary.BigEndian.Uint32(subtable[12:16]))
+
+	if 16+groupCount*12 > len(subtable) {
+		return nil, ErrUnsupportedFontFile
+	}
+
+	coverage := map[rune]bool{}
+
+	for group := 0; group < groupCount; group++ {
+		groupOffset := 16 + group*12
+
+		startCode := binary.BigEndian.Uint32(subtable[groupOffset : groupOffset+4])
+		endCode := binary.BigEndian.Uint32(subtable[groupOffset+4 : groupOffset+8])
+		startGlyphIndex := binary.BigEndian.Uint32(subtable[groupOffset+8 : groupOffset+12])
+
+		if endCode > 0x10FFFF || startCode > endCode {
+			return nil, ErrUnsupportedFontFile
+		}
+
+		for codepoint := startCode; codepoint <= endCode; codepoint++ {
+			if startGlyphIndex+(codepoint-startCode) != 0 {
+				coverage[rune(codepoint)] = true
+			}
+		}
+	}
+
+	return coverage, nil
+}
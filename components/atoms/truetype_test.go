@@ -0,0 +1,55 @@
+package atoms
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCodepointCoverage(t *testing.T) {
+	fontData, err := os.ReadFile(filepath.Join("..", "..", "assets-for-testing", "Roboto-Regular.ttf"))
+	if err != nil {
+		t.Fatalf("Couldn't read the test font: %s", err)
+	}
+
+	coverage, err := readCodepointCoverage(fontData)
+	if err != nil {
+		t.Fatalf("Couldn't read the character map: %s", err)
+	}
+
+	for _, codepoint := range "Hello world! Zażółć gęślą jaźń" {
+		if !coverage[codepoint] {
+			t.Errorf("Expected %q to be covered by the font", codepoint)
+		}
+	}
+
+	for _, codepoint := range "日本語😀" {
+		if coverage[codepoint] {
+			t.Errorf("Expected %q not to be covered by the font", codepoint)
+		}
+	}
+
+	if _, err := readCodepointCoverage([]byte("not a font")); err != ErrUnsupportedFontFile {
+		t.Errorf("Expected ErrUnsupportedFontFile, received: %v", err)
+	}
+}
+
+func TestGetGlyphSizeBucket(t *testing.T) {
+	testCases := []struct {
+		fontSize       float32
+		expectedBucket int32
+	}{
+		{5, 8},
+		{16, 16},
+		{17, 20},
+		{32, 32},
+		{33, 48},
+		{300, 320},
+	}
+
+	for _, testCase := range testCases {
+		if bucket := getGlyphSizeBucket(testCase.fontSize); bucket != testCase.expectedBucket {
+			t.Errorf("Expected bucket %d for font size %f, received %d", testCase.expectedBucket, testCase.fontSize, bucket)
+		}
+	}
+}
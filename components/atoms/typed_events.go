@@ -0,0 +1,50 @@
+package atoms
+
+// Event is a typed key of an event, it makes sure that all publishers and subscribers of the event agree on its payload type.
+// Typed events are dispatched through the string API of the EventBus, so both APIs can be mixed for the same event.
+type Event[T any] struct {
+	name string
+}
+
+func NewEvent[T any](name string) Event[T] {
+	return Event[T]{name}
+}
+
+func (event Event[T]) Name() string {
+	return event.name
+}
+
+// Subscribe registers a callback of a typed event. Payloads of other types, dispatched with the string API, are ignored.
+// An event dispatched without any payload (or with nil) is passed to the callback as the zero value of T.
+func Subscribe[T any](eventBus *EventBus, event Event[T], callback func(payload T)) UnsubscribeFunc {
+	return eventBus.ListenToEvent(event.name, typedCallback(callback))
+}
+
+func SubscribeWithPriority[T any](eventBus *EventBus, event Event[T], priority int, callback func(payload T)) UnsubscribeFunc {
+	return eventBus.ListenToEventWithPriority(event.name, priority, typedCallback(callback))
+}
+
+// SubscribeOnce registers a callback which is removed right before it is called for the first time.
+func SubscribeOnce[T any](eventBus *EventBus, event Event[T], callback func(payload T)) UnsubscribeFunc {
+	return eventBus.ListenOnce(event.name, typedCallback(callback))
+}
+
+func typedCallback[T any](callback func(payload T)) EventCallback {
+	return func(args ...interface{}) {
+		var payload T
+
+		if len(args) > 0 && args[0] != nil {
+			var ok bool
+
+			if payload, ok = args[0].(T); !ok {
+				return
+			}
+		}
+
+		callback(payload)
+	}
+}
+
+func Publish[T any](eventBus *EventBus, event Event[T], payload T) {
+	eventBus.DispatchEvent(event.name, payload)
+}
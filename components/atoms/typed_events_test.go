@@ -0,0 +1,75 @@
+package atoms
+
+import "testing"
+
+type testEventArgs struct {
+	Value int
+}
+
+func TestTypedEvents(t *testing.T) {
+	testEvent := NewEvent[testEventArgs]("test:event")
+
+	t.Run("Publish and subscribe", func(t *testing.T) {
+		eventBus := NewEventBus()
+		received := []int{}
+
+		Subscribe(eventBus, testEvent, func(payload testEventArgs) {
+			received = append(received, payload.Value)
+		})
+
+		Publish(eventBus, testEvent, testEventArgs{Value: 1})
+		eventBus.DispatchEvent("test:event", testEventArgs{Value: 2})
+
+		if len(received) != 2 || received[0] != 1 || received[1] != 2 {
+			t.Errorf("Expected payloads [1 2], received: %v", received)
+		}
+	})
+
+	t.Run("Payloads of other types are ignored", func(t *testing.T) {
+		eventBus := NewEventBus()
+		calls := 0
+
+		Subscribe(eventBus, testEvent, func(payload testEventArgs) {
+			calls++
+		})
+
+		eventBus.DispatchEvent("test:event", "not the payload")
+
+		if calls != 0 {
+			t.Errorf("Expected the callback not to be called, it was called %d times", calls)
+		}
+	})
+
+	t.Run("Missing payload is the zero value", func(t *testing.T) {
+		eventBus := NewEventBus()
+		emptyEvent := NewEvent[struct{}]("test:empty")
+		calls := 0
+
+		Subscribe(eventBus, emptyEvent, func(payload struct{}) {
+			calls++
+		})
+
+		eventBus.DispatchEvent("test:empty", nil)
+		eventBus.DispatchEvent("test:empty")
+
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, received: %d", calls)
+		}
+	})
+
+	t.Run("String listeners receive typed payloads", func(t *testing.T) {
+		eventBus := NewEventBus()
+
+		var received interface{}
+
+		eventBus.ListenToEvent(testEvent.Name(), func(args ...interface{}) {
+			received = args[0]
+		})
+
+		Publish(eventBus, testEvent, testEventArgs{Value: 3})
+
+		if payload, ok := received.(testEventArgs); !ok || payload.Value != 3 {
+			t.Errorf("Expected the typed payload, received: %v", received)
+		}
+	})
+}
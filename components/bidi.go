@@ -0,0 +1,344 @@
+package components
+
+import "unicode"
+
+// Bidirectional character types, as defined by the Unicode bidirectional algorithm:
+// https://www.unicode.org/reports/tr9/
+// Explicit embeddings, overrides and isolates are not supported, their formatting characters are ignored.
+const (
+	bidiClassL   = iota // left-to-right
+	bidiClassR          // right-to-left
+	bidiClassAL         // arabic letter
+	bidiClassEN         // european number
+	bidiClassES         // european number separator
+	bidiClassET         // european number terminator
+	bidiClassAN         // arabic number
+	bidiClassCS         // common number separator
+	bidiClassNSM        // nonspacing mark
+	bidiClassBN         // boundary neutral
+	bidiClassB          // paragraph separator
+	bidiClassS          // segment separator
+	bidiClassWS         // whitespace
+	bidiClassON         // other neutrals
+)
+
+var mirroredCharacters = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'\u00AB': '\u00BB', '\u00BB': '\u00AB',
+	'\u2039': '\u203A', '\u203A': '\u2039',
+}
+
+func getBidiClass(character rune) int {
+	switch character {
+	case '\u200E':
+		return bidiClassL
+	case '\u200F':
+		return bidiClassR
+	case '\u061C':
+		return bidiClassAL
+	case '+', '-', '\u207A', '\u207B', '\uFF0B', '\uFF0D':
+		return bidiClassES
+	case '#', '$', '%', '\u00B0', '\u00B1', '\u2030', '\u2031', '\u066A':
+		return bidiClassET
+	case ',', '.', '/', ':', '\u00A0', '\u060C', '\u202F', '\u2044', '\uFF0C', '\uFF0E', '\uFF0F', '\uFF1A':
+		return bidiClassCS
+	case '\n', '\r', '\u001C', '\u001D', '\u001E', '\u0085', '\u2029':
+		return bidiClassB
+	case '\t', '\v', '\u001F':
+		return bidiClassS
+	case ' ', '\f', '\u1680', '\u2028', '\u205F', '\u3000':
+		return bidiClassWS
+	case '\u200B', '\u200C', '\u200D', '\u2060', '\uFEFF', '\u00AD':
+		return bidiClassBN
+	}
+
+	switch {
+	case character >= '0' && character <= '9', character >= '\u06F0' && character <= '\u06F9', character >= '\uFF10' && character <= '\uFF19':
+		return bidiClassEN
+	case character >= '\u0600' && character <= '\u0605', character >= '\u0660' && character <= '\u0669', character == '\u066B', character == '\u066C':
+		return bidiClassAN
+	case character >= '\u00A2' && character <= '\u00A5', character >= '\u20A0' && character <= '\u20CF':
+		return bidiClassET
+	case character >= '\u2000' && character <= '\u200A':
+		return bidiClassWS
+	case unicode.In(character, unicode.Mn, unicode.Me):
+		return bidiClassNSM
+	case (character >= '\u0590' && character <= '\u05FF') || (character >= '\u07C0' && character <= '\u085F') ||
+		(character >= '\uFB1D' && character <= '\uFB4F') || (character >= '\U00010800' && character <= '\U00010FFF'):
+		return bidiClassR
+	case (character >= '\u0600' && character <= '\u07BF') || (character >= '\u0860' && character <= '\u08FF') ||
+		(character >= '\uFB50' && character <= '\uFDFF') || (character >= '\uFE70' && character <= '\uFEFF'):
+		return bidiClassAL
+	case unicode.IsControl(character):
+		return bidiClassBN
+	case unicode.IsLetter(character) || unicode.IsDigit(character) || unicode.Is(unicode.Mc, character):
+		return bidiClassL
+	}
+
+	return bidiClassON
+}
+
+func isStrongBidiClass(class int) bool {
+	return class == bidiClassL || class == bidiClassR || class == bidiClassAL
+}
+
+func isNeutralBidiClass(class int) bool {
+	return class == bidiClassB || class == bidiClassS || class == bidiClassWS || class == bidiClassON
+}
+
+// detectTextDirection finds the direction of the first strongly directional character in text.
+func detectTextDirection(text string) (rightToLeft bool, found bool) {
+	for _, character := range text {
+		switch getBidiClass(character) {
+		case bidiClassL:
+			return false, true
+		case bidiClassR, bidiClassAL:
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+// resolveBidiLevels resolves embedding levels of the characters of a single line, skipping the explicit rules (X1-X10).
+func resolveBidiLevels(runes []rune, rightToLeft bool) []int {
+	baseLevel := 0
+	embeddingClass := bidiClassL
+
+	if rightToLeft {
+		baseLevel = 1
+		embeddingClass = bidiClassR
+	}
+
+	originalClasses := make([]int, len(runes))
+	classes := make([]int, len(runes))
+
+	for i, character := range runes {
+		originalClasses[i] = getBidiClass(character)
+		classes[i] = originalClasses[i]
+	}
+
+	// W1: nonspacing marks (and ignored boundary neutrals) take the type of the previous character.
+	for i, class := range classes {
+		if class == bidiClassNSM || class == bidiClassBN {
+			if i == 0 {
+				classes[i] = embeddingClass
+			} else {
+				classes[i] = classes[i-1]
+			}
+		}
+	}
+
+	// W2 and W3: european numbers after arabic letters become arabic numbers, arabic letters become right-to-left.
+	lastStrongClass := embeddingClass
+
+	for i, class := range classes {
+		switch {
+		case isStrongBidiClass(class):
+			lastStrongClass = class
+		case class == bidiClassEN && lastStrongClass == bidiClassAL:
+			classes[i] = bidiClassAN
+		}
+	}
+
+	for i, class := range classes {
+		if class == bidiClassAL {
+			classes[i] = bidiClassR
+		}
+	}
+
+	// W4: a single separator between two numbers of the same type takes their type.
+	for i := 1; i < len(classes)-1; i++ {
+		previousClass := classes[i-1]
+		nextClass := classes[i+1]
+
+		if previousClass != nextClass {
+			continue
+		}
+
+		if classes[i] == bidiClassES && previousClass == bidiClassEN {
+			classes[i] = bidiClassEN
+		} else if classes[i] == bidiClassCS && (previousClass == bidiClassEN || previousClass == bidiClassAN) {
+			classes[i] = previousClass
+		}
+	}
+
+	// W5: terminators adjacent to european numbers become european numbers.
+	for i := 0; i < len(classes); i++ {
+		if classes[i] != bidiClassET {
+			continue
+		}
+
+		sequenceEnd := i
+
+		for sequenceEnd < len(classes) && classes[sequenceEnd] == bidiClassET {
+			sequenceEnd++
+		}
+
+		if (i > 0 && classes[i-1] == bidiClassEN) || (sequenceEnd < len(classes) && classes[sequenceEnd] == bidiClassEN) {
+			for j := i; j < sequenceEnd; j++ {
+				classes[j] = bidiClassEN
+			}
+		}
+
+		i = sequenceEnd - 1
+	}
+
+	// W6: remaining separators and terminators become other neutrals.
+	for i, class := range classes {
+		if class == bidiClassES || class == bidiClassET || class == bidiClassCS {
+			classes[i] = bidiClassON
+		}
+	}
+
+	// W7: european numbers in left-to-right context become left-to-right.
+	lastStrongClass = embeddingClass
+
+	for i, class := range classes {
+		switch {
+		case class == bidiClassL || class == bidiClassR:
+			lastStrongClass = class
+		case class == bidiClassEN && lastStrongClass == bidiClassL:
+			classes[i] = bidiClassL
+		}
+	}
+
+	// N1 and N2: neutrals take the direction of the surrounding text if it agrees on both sides, the embedding direction otherwise.
+	strongDirection := func(class int) int {
+		if class == bidiClassEN || class == bidiClassAN {
+			return bidiClassR
+		}
+
+		return class
+	}
+
+	for i := 0; i < len(classes); i++ {
+		if !isNeutralBidiClass(classes[i]) {
+			continue
+		}
+
+		sequenceEnd := i
+
+		for sequenceEnd < len(classes) && isNeutralBidiClass(classes[sequenceEnd]) {
+			sequenceEnd++
+		}
+
+		leadingClass := embeddingClass
+		trailingClass := embeddingClass
+
+		if i > 0 {
+			leadingClass = strongDirection(classes[i-1])
+		}
+
+		if sequenceEnd < len(classes) {
+			trailingClass = strongDirection(classes[sequenceEnd])
+		}
+
+		resolvedClass := embeddingClass
+
+		if leadingClass == trailingClass {
+			resolvedClass = leadingClass
+		}
+
+		for j := i; j < sequenceEnd; j++ {
+			classes[j] = resolvedClass
+		}
+
+		i = sequenceEnd - 1
+	}
+
+	// I1 and I2: implicit levels.
+	levels := make([]int, len(runes))
+
+	for i, class := range classes {
+		levels[i] = baseLevel
+
+		if baseLevel%2 == 0 {
+			switch class {
+			case bidiClassR:
+				levels[i] += 1
+			case bidiClassAN, bidiClassEN:
+				levels[i] += 2
+			}
+		} else if class == bidiClassL || class == bidiClassEN || class == bidiClassAN {
+			levels[i] += 1
+		}
+	}
+
+	// L1: separators and the whitespace before them, as well as the whitespace at the end of the line, get the base level.
+	trailingWhitespace := true
+
+	for i := len(runes) - 1; i >= 0; i-- {
+		switch originalClasses[i] {
+		case bidiClassS, bidiClassB:
+			levels[i] = baseLevel
+			trailingWhitespace = true
+		case bidiClassWS, bidiClassBN:
+			if trailingWhitespace {
+				levels[i] = baseLevel
+			}
+		default:
+			trailingWhitespace = false
+		}
+	}
+
+	return levels
+}
+
+// reorderLine returns the characters of a single line in visual order (from left to right), following rules L2 and L4.
+func reorderLine(text string, rightToLeft bool) string {
+	runes := []rune(text)
+	levels := resolveBidiLevels(runes, rightToLeft)
+
+	highestLevel := 0
+	lowestOddLevel := -1
+
+	for _, level := range levels {
+		if level > highestLevel {
+			highestLevel = level
+		}
+
+		if level%2 == 1 && (lowestOddLevel == -1 || level < lowestOddLevel) {
+			lowestOddLevel = level
+		}
+	}
+
+	if lowestOddLevel == -1 {
+		return text
+	}
+
+	visualRunes := make([]rune, len(runes))
+	copy(visualRunes, runes)
+
+	for i, level := range levels {
+		if mirroredCharacter, ok := mirroredCharacters[visualRunes[i]]; ok && level%2 == 1 {
+			visualRunes[i] = mirroredCharacter
+		}
+	}
+
+	for level := highestLevel; level >= lowestOddLevel; level-- {
+		for i := 0; i < len(visualRunes); i++ {
+			if levels[i] < level {
+				continue
+			}
+
+			sequenceEnd := i
+
+			for sequenceEnd < len(visualRunes) && levels[sequenceEnd] >= level {
+				sequenceEnd++
+			}
+
+			for left, right := i, sequenceEnd-1; left < right; left, right = left+1, right-1 {
+				visualRunes[left], visualRunes[right] = visualRunes[right], visualRunes[left]
+				levels[left], levels[right] = levels[right], levels[left]
+			}
+
+			i = sequenceEnd - 1
+		}
+	}
+
+	return string(visualRunes)
+}
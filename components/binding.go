@@ -0,0 +1,27 @@
+package components
+
+import "domanscy.group/gui/components/atoms"
+
+// optionalSignal is implemented by signals which can have no value yet, like tokens missing in the current theme.
+type optionalSignal[T comparable] interface {
+	getOptional() (T, bool)
+}
+
+// bind calls the setter with the current value of the signal, and again every time it changes.
+// Bindings live as long as the signal, unless the returned function is called, e.g. by passing it to ComponentLifecycle.ReleaseOnUnmount.
+func bind[T comparable](signal atoms.ReadonlySignal[T], set func(value T)) atoms.UnsubscribeFunc {
+	effect := atoms.NewEffect(func() {
+		if optional, ok := signal.(optionalSignal[T]); ok {
+			// The setter is skipped until the signal has a value, so the component keeps its own one.
+			if value, found := optional.getOptional(); found {
+				set(value)
+			}
+
+			return
+		}
+
+		set(signal.Get())
+	})
+
+	return effect.Dispose
+}
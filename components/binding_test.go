@@ -0,0 +1,73 @@
+package components
+
+import (
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestBindings(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	recalculations := 0
+	atoms.Subscribe(eventBus, ScheduleRecalculationEvent, func(struct{}) {
+		recalculations++
+	})
+
+	t.Run("Text changes schedule a relayout", func(t *testing.T) {
+		recalculations = 0
+
+		count := atoms.NewSignal(1)
+		label := atoms.NewComputed(func() string {
+			if count.Get() == 1 {
+				return "1 item"
+			}
+
+			return "many items"
+		})
+
+		text := NewTextComponent(eventBus, "", "Roboto", 16, 0, rl.White)
+		unbind := text.BindText(label)
+
+		if text.GetText() != "1 item" {
+			t.Errorf("Expected the bound text, got %q", text.GetText())
+		}
+
+		count.Set(2)
+		count.Set(3)
+
+		if text.GetText() != "many items" || recalculations != 2 {
+			t.Errorf("Expected a relayout only when the text changes, got %q and %d relayouts", text.GetText(), recalculations)
+		}
+
+		unbind()
+		count.Set(1)
+
+		if text.GetText() != "many items" {
+			t.Errorf("Expected the text to stay the same after unbinding, got %q", text.GetText())
+		}
+	})
+
+	t.Run("Color changes don't schedule a relayout", func(t *testing.T) {
+		recalculations = 0
+
+		color := atoms.NewSignal(rl.White)
+
+		rectangle, _ := NewRectangleComponent(eventBus, nil, rl.Blank, 0)
+		rectangle.BindBackgroundColor(color)
+		color.Set(rl.Red)
+
+		if rectangle.GetBackgroundColor() != rl.Red || recalculations != 0 {
+			t.Errorf("Expected a red background without relayouts, got %v and %d relayouts", rectangle.GetBackgroundColor(), recalculations)
+		}
+
+		padding := atoms.NewSignal[float32](0)
+		rectangle.BindPadding(padding)
+		padding.Set(8)
+
+		if rectangle.GetPaddingLeft() != 8 || rectangle.GetPaddingBottom() != 8 || recalculations != 1 {
+			t.Errorf("Expected padding 8 after a single relayout, got %f and %d relayouts", rectangle.GetPaddingLeft(), recalculations)
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package components
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestLayoutChildren(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	recalculations := 0
+	atoms.Subscribe(eventBus, ScheduleRecalculationEvent, func(struct{}) {
+		recalculations++
+	})
+
+	log := []string{}
+	a := newHookedComponent(eventBus, "a", &log)
+	b := newHookedComponent(eventBus, "b", &log)
+	c := newHookedComponent(eventBus, "c", &log)
+	d := newHookedComponent(eventBus, "d", &log)
+
+	layout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	MountTree(layout)
+
+	expectChildren := func(t *testing.T, expected ...Component) {
+		t.Helper()
+
+		if !slices.Equal(layout.GetChildren(), expected) {
+			t.Errorf("Expected children %v, got %v", expected, layout.GetChildren())
+		}
+	}
+
+	t.Run("Adding and inserting", func(t *testing.T) {
+		layout.AddChild(a)
+		layout.AddChild(c)
+
+		if err := layout.InsertChild(1, b); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := layout.InsertChild(5, d); !errors.Is(err, ErrChildIndexOutOfRange) {
+			t.Errorf("Expected ErrChildIndexOutOfRange, got %v", err)
+		}
+
+		expectChildren(t, a, b, c)
+
+		if !slices.Equal(log, []string{"mount a", "mount c", "mount b"}) {
+			t.Errorf("Expected the children to be mounted, got %v", log)
+		}
+	})
+
+	t.Run("Moving", func(t *testing.T) {
+		if err := layout.MoveChild(c, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		expectChildren(t, c, a, b)
+
+		if err := layout.MoveChild(c, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		expectChildren(t, a, b, c)
+
+		if err := layout.MoveChild(d, 0); !errors.Is(err, ErrChildNotFound) {
+			t.Errorf("Expected ErrChildNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Removing and replacing", func(t *testing.T) {
+		log = []string{}
+
+		if err := layout.ReplaceChild(b, d); err != nil {
+			t.Fatal(err)
+		}
+
+		expectChildren(t, a, d, c)
+
+		if err := layout.RemoveChild(b); !errors.Is(err, ErrChildNotFound) {
+			t.Errorf("Expected ErrChildNotFound, got %v", err)
+		}
+
+		if err := layout.RemoveChildAt(0); err != nil {
+			t.Fatal(err)
+		}
+
+		expectChildren(t, d, c)
+
+		layout.ClearChildren()
+
+		expectChildren(t)
+
+		expectedLog := []string{"unmount b", "mount d", "unmount a", "unmount d", "unmount c"}
+		if !slices.Equal(log, expectedLog) {
+			t.Errorf("Expected hooks %v, got %v", expectedLog, log)
+		}
+	})
+
+	// Every successful change schedules a relayout: 3 additions, 2 moves, a replacement (2), a removal and clearing 2 children.
+	if recalculations != 10 {
+		t.Errorf("Expected 10 scheduled recalculations, got %d", recalculations)
+	}
+}
+
+func TestRectangleChild(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+	log := []string{}
+
+	rectangle, _ := NewRectangleComponent(eventBus, nil, rl.Blank, 0)
+	rectangle.SetPaddingTop(4)
+	rectangle.SetPaddingLeft(6)
+
+	size, err := rectangle.CalculateSize(nil, rl.NewVector2(100, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if size != rl.NewVector2(6, 4) {
+		t.Errorf("Expected a rectangle without a child to be as big as its padding, got %v", size)
+	}
+
+	MountTree(rectangle)
+
+	child := newHookedComponent(eventBus, "a", &log)
+	rectangle.SetChild(child)
+	rectangle.SetChild(child)
+	rectangle.RemoveChild()
+
+	if len(rectangle.GetChildren()) != 0 {
+		t.Errorf("Expected no children, got %v", rectangle.GetChildren())
+	}
+
+	if !slices.Equal(log, []string{"mount a", "unmount a"}) {
+		t.Errorf("Expected the child to be mounted and unmounted once, got %v", log)
+	}
+}
+
+// siblingRemover removes its sibling from the layout when it's unmounted.
+type siblingRemover struct {
+	*hookedComponent
+
+	layout  *LayoutComponent
+	sibling Component
+}
+
+func (comp *siblingRemover) OnUnmount() {
+	comp.hookedComponent.OnUnmount()
+	_ = comp.layout.RemoveChild(comp.sibling)
+}
+
+func TestChangingChildrenInHooks(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+	log := []string{}
+
+	layout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	b := newHookedComponent(eventBus, "b", &log)
+
+	t.Run("Removing a sibling while the tree is unmounted", func(t *testing.T) {
+		remover := &siblingRemover{newHookedComponent(eventBus, "remover", &log), layout, b}
+		layout.AddChild(remover)
+		layout.AddChild(b)
+
+		MountTree(layout)
+		UnmountTree(layout)
+
+		if !slices.Equal(layout.GetChildren(), []Component{remover}) {
+			t.Errorf("Expected the sibling to be removed, got %v", layout.GetChildren())
+		}
+	})
+
+	t.Run("Removing a sibling during a walk", func(t *testing.T) {
+		layout.AddChild(b)
+
+		visited := []Component{}
+
+		Walk(layout, func(component Component) bool {
+			if component.GetEventBus() == eventBus {
+				visited = append(visited, component)
+			}
+
+			if component == layout.GetChildren()[0] {
+				_ = layout.RemoveChild(b)
+			}
+
+			return true
+		})
+
+		if len(visited) != 3 {
+			t.Errorf("Expected the children from before the walk to be visited, got %v", visited)
+		}
+	})
+}
+
+func TestReparentingChildren(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+	log := []string{}
+
+	a := newHookedComponent(eventBus, "a", &log)
+	b := newHookedComponent(eventBus, "b", &log)
+	c := newHookedComponent(eventBus, "c", &log)
+
+	layout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	otherLayout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	rectangle, _ := NewRectangleComponent(eventBus, nil, rl.Blank, 0)
+
+	layout.AddChild(a)
+	layout.AddChild(b)
+	otherLayout.AddChild(c)
+
+	t.Run("Inserting a child of another container", func(t *testing.T) {
+		if err := otherLayout.InsertChild(0, a); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(layout.GetChildren(), []Component{b}) || !slices.Equal(otherLayout.GetChildren(), []Component{a, c}) {
+			t.Errorf("Expected a to be moved, got %v and %v", layout.GetChildren(), otherLayout.GetChildren())
+		}
+
+		if a.GetParent() != otherLayout {
+			t.Errorf("Expected the new parent of a, got %v", a.GetParent())
+		}
+	})
+
+	t.Run("Inserting a child at the end of its own layout", func(t *testing.T) {
+		if err := otherLayout.InsertChild(2, a); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(otherLayout.GetChildren(), []Component{c, a}) {
+			t.Errorf("Expected a to be moved to the end, got %v", otherLayout.GetChildren())
+		}
+	})
+
+	t.Run("Replacing with a child of another container", func(t *testing.T) {
+		rectangle.SetChild(c)
+
+		if err := layout.ReplaceChild(b, c); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rectangle.GetChildren()) != 0 || !slices.Equal(layout.GetChildren(), []Component{c}) {
+			t.Errorf("Expected c to be moved from the rectangle, got %v and %v", rectangle.GetChildren(), layout.GetChildren())
+		}
+
+		if b.GetParent() != nil || c.GetParent() != layout {
+			t.Errorf("Expected b to be detached and c attached to the layout")
+		}
+	})
+
+	t.Run("Creating a rectangle with a child of another container", func(t *testing.T) {
+		newRectangle, _ := NewRectangleComponent(eventBus, c, rl.Blank, 0)
+
+		if len(layout.GetChildren()) != 0 || c.GetParent() != newRectangle {
+			t.Errorf("Expected c to be moved to the new rectangle, got %v and %v", layout.GetChildren(), c.GetParent())
+		}
+	})
+}
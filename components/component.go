@@ -5,11 +5,12 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-type GetFontCallback = func(fontName string) (rl.Font, error)
+// GetFontCallback returns the variant of the font family closest to the given weight and style.
+type GetFontCallback = func(fontName string, fontWeight int, fontStyle int) (*atoms.FontChain, error)
 
 type Component interface {
-	Render(GetFontCallback)
-	CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) rl.Vector2
+	Render(GetFontCallback) error
+	CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) (rl.Vector2, error)
 	SetPosition(rl.Vector2)
 	SetPositionOffset(rl.Vector2)
 	GetPosition() rl.Vector2
@@ -0,0 +1,133 @@
+package components
+
+import (
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const elementLayout = 0
+const elementRectangle = 1
+const elementText = 2
+
+// Element is a lightweight description of a component. A tree of elements is rendered by a Reconciler,
+// which creates components the first time and later only patches the properties that changed.
+type Element struct {
+	kind     int
+	key      string
+	id       string
+	tags     []string
+	children []*Element
+
+	direction          int
+	mainAxisAlignment  int
+	crossAxisAlignment int
+
+	backgroundColor rl.Color
+	roundness       float32
+	padding         atoms.ClockValues
+
+	text       string
+	fontName   string
+	fontSize   float32
+	spacing    float32
+	color      rl.Color
+	wrapText   bool
+	textAlign  int
+	fontWeight int
+	fontStyle  int
+}
+
+func LayoutElement(direction int, mainAxisAlignment int, crossAxisAlignment int, children ...*Element) *Element {
+	return &Element{
+		kind:     elementLayout,
+		tags:     []string{},
+		children: children,
+
+		direction:          direction,
+		mainAxisAlignment:  mainAxisAlignment,
+		crossAxisAlignment: crossAxisAlignment,
+	}
+}
+
+// RectangleElement describes a rectangle, child can be nil.
+func RectangleElement(backgroundColor rl.Color, roundness float32, child *Element) *Element {
+	children := []*Element{}
+
+	if child != nil {
+		children = append(children, child)
+	}
+
+	return &Element{
+		kind:     elementRectangle,
+		tags:     []string{},
+		children: children,
+
+		backgroundColor: backgroundColor,
+		roundness:       roundness,
+		padding:         atoms.NewClockValues(),
+	}
+}
+
+func TextElement(text string, fontName string, fontSize float32, spacing float32, color rl.Color) *Element {
+	return &Element{
+		kind:     elementText,
+		tags:     []string{},
+		children: []*Element{},
+
+		text:       text,
+		fontName:   fontName,
+		fontSize:   fontSize,
+		spacing:    spacing,
+		color:      color,
+		textAlign:  TextAlignStart,
+		fontWeight: FontWeightNormal,
+		fontStyle:  FontStyleNormal,
+	}
+}
+
+// WithKey identifies the element among its siblings, so its component is kept (and moved) when the siblings are reordered.
+// Elements without keys are matched with the previous ones by their order.
+func (element *Element) WithKey(key string) *Element {
+	element.key = key
+	return element
+}
+
+func (element *Element) WithID(id string) *Element {
+	element.id = id
+	return element
+}
+
+func (element *Element) WithTags(tags ...string) *Element {
+	element.tags = append(element.tags, tags...)
+	return element
+}
+
+// WithPadding is used only by rectangle elements.
+func (element *Element) WithPadding(top float32, right float32, bottom float32, left float32) *Element {
+	element.padding.SetTop(top)
+	element.padding.SetRight(right)
+	element.padding.SetBottom(bottom)
+	element.padding.SetLeft(left)
+	return element
+}
+
+// WithWrapText is used only by text elements, like the other text properties.
+func (element *Element) WithWrapText(wrap bool) *Element {
+	element.wrapText = wrap
+	return element
+}
+
+func (element *Element) WithTextAlign(textAlign int) *Element {
+	element.textAlign = textAlign
+	return element
+}
+
+func (element *Element) WithFontWeight(fontWeight int) *Element {
+	element.fontWeight = fontWeight
+	return element
+}
+
+func (element *Element) WithFontStyle(fontStyle int) *Element {
+	element.fontStyle = fontStyle
+	return element
+}
@@ -0,0 +1,15 @@
+package components
+
+import "errors"
+
+// ErrInvalidProperty is returned by constructors and setters of components, when a value is out of its range.
+var ErrInvalidProperty = errors.New("invalid property value")
+
+// ErrFontNotLoaded is returned by CalculateSize and Render of text components, which font wasn't added to the app.
+var ErrFontNotLoaded = errors.New("font is not loaded")
+
+// ErrChildNotFound is returned by containers, when the child to remove, replace or move isn't one of their children.
+var ErrChildNotFound = errors.New("child not found")
+
+// ErrChildIndexOutOfRange is returned by containers, when the index of a child is outside of their children.
+var ErrChildIndexOutOfRange = errors.New("child index out of range")
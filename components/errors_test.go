@@ -0,0 +1,70 @@
+package components
+
+import (
+	"errors"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestInvalidProperties(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	t.Run("Layout constructor", func(t *testing.T) {
+		if _, err := NewLayoutComponent(eventBus, 5, AlignStart, AlignStart); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for an unknown direction, received: %v", err)
+		}
+
+		if _, err := NewLayoutComponent(eventBus, DirectionRow, AlignStart, 5); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for an unknown alignment, received: %v", err)
+		}
+
+		if _, err := NewLayoutComponent(eventBus, DirectionRow, AlignCenter, AlignEnd); err != nil {
+			t.Errorf("Expected no error, received: %v", err)
+		}
+	})
+
+	t.Run("Rectangle constructor", func(t *testing.T) {
+		if _, err := NewRectangleComponent(eventBus, nil, rl.Blank, -1); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for a negative roundness, received: %v", err)
+		}
+	})
+
+	t.Run("Text setters", func(t *testing.T) {
+		text := NewTextComponent(eventBus, "Hello", "Roboto", 32, 0, rl.White)
+
+		if err := text.SetTextAlign(10); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for an unknown text alignment, received: %v", err)
+		}
+
+		if err := text.SetFontWeight(450); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for a font weight between the standard ones, received: %v", err)
+		}
+
+		if err := text.SetMaxLines(-1); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty for negative max lines, received: %v", err)
+		}
+	})
+}
+
+func TestMissingFont(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+	errUnknownFont := errors.New("unknown font")
+
+	getFont := func(fontName string, fontWeight int, fontStyle int) (*atoms.FontChain, error) {
+		return nil, errUnknownFont
+	}
+
+	text := NewTextComponent(eventBus, "Hello", "Robot", 32, 0, rl.White)
+	rectangle, err := NewRectangleComponent(eventBus, text, rl.Blank, 0)
+	if err != nil {
+		t.Fatalf("Couldn't create the rectangle: %s", err)
+	}
+
+	_, err = rectangle.CalculateSize(getFont, rl.Vector2{X: 800, Y: 600})
+
+	if !errors.Is(err, ErrFontNotLoaded) || !errors.Is(err, errUnknownFont) {
+		t.Errorf("Expected the error of the font to be passed up, received: %v", err)
+	}
+}
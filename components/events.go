@@ -0,0 +1,6 @@
+package components
+
+import "domanscy.group/gui/components/atoms"
+
+// ScheduleRecalculationEvent makes the app calculate sizes and positions of components again, on the next frame.
+var ScheduleRecalculationEvent = atoms.NewEvent[struct{}]("gui:schedule-recalculation")
@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"slices"
 
 	"domanscy.group/gui/components/atoms"
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -14,49 +15,132 @@ const AlignStart = 0
 const AlignCenter = 1
 const AlignEnd = 2
 
+const LayoutDirectionLTR = 0
+const LayoutDirectionRTL = 1
+
 type LayoutComponent struct {
+	ComponentNode
+
 	children           []Component
 	direction          int
 	mainAxisAlignment  int
 	crossAxisAlignment int
+	layoutDirection    int
+	size               rl.Vector2
 
-	position ComponentPosition
+	position  ComponentPosition
+	lifecycle ComponentLifecycle
 
 	eventBus *atoms.EventBus
 }
 
-func NewLayoutComponent(eventBus *atoms.EventBus, direction int, mainAxisAlignment int, crossAxisAlignment int) *LayoutComponent {
+func NewLayoutComponent(eventBus *atoms.EventBus, direction int, mainAxisAlignment int, crossAxisAlignment int) (*LayoutComponent, error) {
 	if direction != DirectionColumn && direction != DirectionRow {
-		panic(fmt.Sprintf("Unknown value for direction property: %d", direction))
+		return nil, fmt.Errorf("%w: unknown value for direction property: %d", ErrInvalidProperty, direction)
 	}
 
 	if mainAxisAlignment != AlignCenter && mainAxisAlignment != AlignStart && mainAxisAlignment != AlignEnd {
-		panic(fmt.Sprintf("Unknwon value for mainAxisAlignment property: %d", mainAxisAlignment))
+		return nil, fmt.Errorf("%w: unknown value for mainAxisAlignment property: %d", ErrInvalidProperty, mainAxisAlignment)
 	}
 
 	if crossAxisAlignment != AlignCenter && crossAxisAlignment != AlignStart && crossAxisAlignment != AlignEnd {
-		panic(fmt.Sprintf("Unknwon value for crossAxisAlignment property: %d", crossAxisAlignment))
+		return nil, fmt.Errorf("%w: unknown value for crossAxisAlignment property: %d", ErrInvalidProperty, crossAxisAlignment)
 	}
 
 	return &LayoutComponent{
+		ComponentNode: NewComponentNode(),
+
 		children:           make([]Component, 0),
 		direction:          direction,
 		mainAxisAlignment:  mainAxisAlignment,
 		crossAxisAlignment: crossAxisAlignment,
+		layoutDirection:    LayoutDirectionLTR,
+		size:               rl.Vector2Zero(),
 		position:           NewComponentPosition(),
+		lifecycle:          NewComponentLifecycle(),
 
 		eventBus: eventBus,
+	}, nil
+}
+
+// SetLayoutDirection mirrors the layout horizontally for right-to-left locales:
+// rows are filled from right to left and AlignStart/AlignEnd swap sides on the horizontal axis.
+func (layout *LayoutComponent) SetLayoutDirection(layoutDirection int) error {
+	if layoutDirection != LayoutDirectionLTR && layoutDirection != LayoutDirectionRTL {
+		return fmt.Errorf("%w: unknown value for layoutDirection property: %d", ErrInvalidProperty, layoutDirection)
 	}
+
+	layout.layoutDirection = layoutDirection
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
 }
 
-func (layout *LayoutComponent) calculateSizesOfChildren(getFont GetFontCallback, maxViewport rl.Vector2) []rl.Vector2 {
+func (layout *LayoutComponent) SetDirection(direction int) error {
+	if direction != DirectionColumn && direction != DirectionRow {
+		return fmt.Errorf("%w: unknown value for direction property: %d", ErrInvalidProperty, direction)
+	}
+
+	layout.direction = direction
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (layout *LayoutComponent) SetMainAxisAlignment(mainAxisAlignment int) error {
+	if mainAxisAlignment != AlignCenter && mainAxisAlignment != AlignStart && mainAxisAlignment != AlignEnd {
+		return fmt.Errorf("%w: unknown value for mainAxisAlignment property: %d", ErrInvalidProperty, mainAxisAlignment)
+	}
+
+	layout.mainAxisAlignment = mainAxisAlignment
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (layout *LayoutComponent) SetCrossAxisAlignment(crossAxisAlignment int) error {
+	if crossAxisAlignment != AlignCenter && crossAxisAlignment != AlignStart && crossAxisAlignment != AlignEnd {
+		return fmt.Errorf("%w: unknown value for crossAxisAlignment property: %d", ErrInvalidProperty, crossAxisAlignment)
+	}
+
+	layout.crossAxisAlignment = crossAxisAlignment
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func mirrorAlignment(alignment int) int {
+	switch alignment {
+	case AlignStart:
+		return AlignEnd
+	case AlignEnd:
+		return AlignStart
+	default:
+		return alignment
+	}
+}
+
+func reverse[T any](values []T) []T {
+	reversed := make([]T, len(values))
+
+	for i, value := range values {
+		reversed[len(values)-1-i] = value
+	}
+
+	return reversed
+}
+
+func (layout *LayoutComponent) calculateSizesOfChildren(getFont GetFontCallback, maxViewport rl.Vector2) ([]rl.Vector2, error) {
 	sizes := make([]rl.Vector2, len(layout.children))
 
 	currentMaxViewport := maxViewport
 
 	if layout.direction == DirectionColumn {
 		for i, child := range layout.children {
-			childSize := child.CalculateSize(getFont, currentMaxViewport)
+			childSize, err := child.CalculateSize(getFont, currentMaxViewport)
+			if err != nil {
+				return nil, err
+			}
 
 			sizes[i] = childSize
 
@@ -64,7 +148,10 @@ func (layout *LayoutComponent) calculateSizesOfChildren(getFont GetFontCallback,
 		}
 	} else {
 		for i, child := range layout.children {
-			childSize := child.CalculateSize(getFont, currentMaxViewport)
+			childSize, err := child.CalculateSize(getFont, currentMaxViewport)
+			if err != nil {
+				return nil, err
+			}
 
 			sizes[i] = childSize
 
@@ -72,7 +159,7 @@ func (layout *LayoutComponent) calculateSizesOfChildren(getFont GetFontCallback,
 		}
 	}
 
-	return sizes
+	return sizes, nil
 }
 
 func getArrayOfXAxisFromVector2(arr []rl.Vector2) []float32 {
@@ -105,12 +192,20 @@ func sum[T float32](values []T) T {
 	return result
 }
 
-func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForMainAxis(sizes []float32, maxViewport float32) (positions []float32, parentSize float32) {
+func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForMainAxis(sizes []float32, maxViewport float32, mirrored bool) (positions []float32, parentSize float32) {
+	mainAxisAlignment := layout.mainAxisAlignment
+
+	if mirrored {
+		// Children are laid out in reversed order, starting from the opposite side.
+		sizes = reverse(sizes)
+		mainAxisAlignment = mirrorAlignment(mainAxisAlignment)
+	}
+
 	positions = make([]float32, len(sizes))
 
 	var currentPos float32
 
-	switch layout.mainAxisAlignment {
+	switch mainAxisAlignment {
 	case AlignStart:
 		currentPos = 0
 
@@ -152,16 +247,26 @@ func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForMainAxis(s
 		}
 		break
 	default:
-		panic(fmt.Sprintf("Unhandled main axis alignment value: %d", layout.mainAxisAlignment))
+		panic(fmt.Sprintf("Unhandled main axis alignment value: %d", mainAxisAlignment))
+	}
+
+	if mirrored {
+		positions = reverse(positions)
 	}
 
 	return
 }
 
-func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForCrossAxis(sizes []float32, maxViewport float32) (positions []float32, parentSize float32) {
+func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForCrossAxis(sizes []float32, maxViewport float32, mirrored bool) (positions []float32, parentSize float32) {
+	crossAxisAlignment := layout.crossAxisAlignment
+
+	if mirrored {
+		crossAxisAlignment = mirrorAlignment(crossAxisAlignment)
+	}
+
 	positions = make([]float32, len(sizes))
 
-	switch layout.crossAxisAlignment {
+	switch crossAxisAlignment {
 	case AlignStart:
 		var maxChildSize float32 = 0
 
@@ -212,7 +317,7 @@ func (layout *LayoutComponent) calculateChildPositionsAndParentSizeForCrossAxis(
 		}
 		break
 	default:
-		panic(fmt.Sprintf("Unhandled cross axis alignment value: %d", layout.crossAxisAlignment))
+		panic(fmt.Sprintf("Unhandled cross axis alignment value: %d", crossAxisAlignment))
 	}
 
 	return
@@ -233,8 +338,11 @@ func joinFloatArraysToVector2Array(xArr []float32, yArr []float32) []rl.Vector2
 	return joinedArr
 }
 
-func (layout *LayoutComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) rl.Vector2 {
-	childrenSizes := layout.calculateSizesOfChildren(getFont, maxViewport)
+func (layout *LayoutComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) (rl.Vector2, error) {
+	childrenSizes, err := layout.calculateSizesOfChildren(getFont, maxViewport)
+	if err != nil {
+		return rl.Vector2Zero(), err
+	}
 
 	xAxisSizes := getArrayOfXAxisFromVector2(childrenSizes)
 	yAxisSizes := getArrayOfYAxisFromVector2(childrenSizes)
@@ -245,17 +353,19 @@ func (layout *LayoutComponent) CalculateSize(getFont GetFontCallback, maxViewpor
 	var xAxisParentSize float32
 	var yAxisParentSize float32
 
+	rightToLeft := layout.layoutDirection == LayoutDirectionRTL
+
 	switch layout.direction {
 	case DirectionColumn:
-		yAxisPositions, yAxisParentSize = layout.calculateChildPositionsAndParentSizeForMainAxis(yAxisSizes, maxViewport.Y)
-		xAxisPositions, xAxisParentSize = layout.calculateChildPositionsAndParentSizeForCrossAxis(xAxisSizes, maxViewport.X)
+		yAxisPositions, yAxisParentSize = layout.calculateChildPositionsAndParentSizeForMainAxis(yAxisSizes, maxViewport.Y, false)
+		xAxisPositions, xAxisParentSize = layout.calculateChildPositionsAndParentSizeForCrossAxis(xAxisSizes, maxViewport.X, rightToLeft)
 		break
 	case DirectionRow:
-		xAxisPositions, xAxisParentSize = layout.calculateChildPositionsAndParentSizeForMainAxis(xAxisSizes, maxViewport.X)
-		yAxisPositions, yAxisParentSize = layout.calculateChildPositionsAndParentSizeForCrossAxis(yAxisSizes, maxViewport.Y)
+		xAxisPositions, xAxisParentSize = layout.calculateChildPositionsAndParentSizeForMainAxis(xAxisSizes, maxViewport.X, rightToLeft)
+		yAxisPositions, yAxisParentSize = layout.calculateChildPositionsAndParentSizeForCrossAxis(yAxisSizes, maxViewport.Y, false)
 		break
 	default:
-		panic(fmt.Sprintf("Unhandled direction parameter value in layout component: %d", layout.direction))
+		return rl.Vector2Zero(), fmt.Errorf("%w: unhandled direction parameter value in layout component: %d", ErrInvalidProperty, layout.direction)
 	}
 
 	positions := joinFloatArraysToVector2Array(xAxisPositions, yAxisPositions)
@@ -264,16 +374,22 @@ func (layout *LayoutComponent) CalculateSize(getFont GetFontCallback, maxViewpor
 		child.SetPosition(positions[i])
 	}
 
-	return rl.Vector2{
+	layout.size = rl.Vector2{
 		X: xAxisParentSize,
 		Y: yAxisParentSize,
 	}
+
+	return layout.size, nil
 }
 
-func (layout *LayoutComponent) Render(getFont GetFontCallback) {
+func (layout *LayoutComponent) Render(getFont GetFontCallback) error {
 	for _, child := range layout.children {
-		child.Render(getFont)
+		if err := child.Render(getFont); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 func (layout *LayoutComponent) SetPosition(pos rl.Vector2) {
@@ -297,7 +413,138 @@ func (layout *LayoutComponent) SetPositionOffset(offset rl.Vector2) {
 }
 
 func (layout *LayoutComponent) AddChild(child Component) {
+	detachFromParent(child)
+
 	layout.children = append(layout.children, child)
+
+	layout.childAdded(child)
+}
+
+// InsertChild puts the child at the index, an index equal to the number of children appends it.
+func (layout *LayoutComponent) InsertChild(index int, child Component) error {
+	if index < 0 || index > len(layout.children) {
+		return fmt.Errorf("%w: can't insert at %d, layout has %d children", ErrChildIndexOutOfRange, index, len(layout.children))
+	}
+
+	detachFromParent(child)
+
+	// The child could be detached from this layout, so there may be one child less now.
+	index = min(index, len(layout.children))
+
+	layout.children = slices.Insert(layout.children, index, child)
+
+	layout.childAdded(child)
+
+	return nil
+}
+
+func (layout *LayoutComponent) RemoveChild(child Component) error {
+	index := slices.Index(layout.children, child)
+	if index == -1 {
+		return ErrChildNotFound
+	}
+
+	return layout.RemoveChildAt(index)
+}
+
+func (layout *LayoutComponent) RemoveChildAt(index int) error {
+	if index < 0 || index >= len(layout.children) {
+		return fmt.Errorf("%w: can't remove %d, layout has %d children", ErrChildIndexOutOfRange, index, len(layout.children))
+	}
+
+	child := layout.children[index]
+	layout.children = slices.Delete(layout.children, index, index+1)
+
+	layout.childRemoved(child)
+
+	return nil
+}
+
+// ReplaceChild puts the new child in place of the old one, which is unmounted.
+func (layout *LayoutComponent) ReplaceChild(oldChild Component, newChild Component) error {
+	if !slices.Contains(layout.children, oldChild) {
+		return ErrChildNotFound
+	}
+
+	if oldChild == newChild {
+		return nil
+	}
+
+	detachFromParent(newChild)
+
+	index := slices.Index(layout.children, oldChild)
+
+	layout.children[index] = newChild
+
+	layout.childRemoved(oldChild)
+	layout.childAdded(newChild)
+
+	return nil
+}
+
+// MoveChild changes the position of the child, so it ends up at the index. The child stays mounted.
+func (layout *LayoutComponent) MoveChild(child Component, index int) error {
+	from := slices.Index(layout.children, child)
+	if from == -1 {
+		return ErrChildNotFound
+	}
+
+	if index < 0 || index >= len(layout.children) {
+		return fmt.Errorf("%w: can't move to %d, layout has %d children", ErrChildIndexOutOfRange, index, len(layout.children))
+	}
+
+	layout.children = slices.Insert(slices.Delete(layout.children, from, from+1), index, child)
+
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (layout *LayoutComponent) ClearChildren() {
+	children := layout.children
+	layout.children = make([]Component, 0)
+
+	for _, child := range children {
+		layout.childRemoved(child)
+	}
+}
+
+func (layout *LayoutComponent) childAdded(child Component) {
+	setParent(child, layout)
+
+	if layout.lifecycle.IsMounted() {
+		MountTree(child)
+	}
+
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+func (layout *LayoutComponent) childRemoved(child Component) {
+	setParent(child, nil)
+
+	if layout.lifecycle.IsMounted() {
+		UnmountTree(child)
+	}
+
+	atoms.Publish(layout.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+// GetChildren returns a copy of the children, so hooks called while it's iterated can change them.
+func (layout *LayoutComponent) GetChildren() []Component {
+	return slices.Clone(layout.children)
+}
+
+func (layout *LayoutComponent) detachChild(child Component) {
+	_ = layout.RemoveChild(child)
+}
+
+func (layout *LayoutComponent) GetLifecycle() *ComponentLifecycle {
+	return &layout.lifecycle
+}
+
+// GetSize returns the size calculated by the last layout.
+func (layout *LayoutComponent) GetSize() rl.Vector2 {
+	return layout.size
 }
 
 func (layout *LayoutComponent) GetPosition() rl.Vector2 {
@@ -307,3 +554,8 @@ func (layout *LayoutComponent) GetPosition() rl.Vector2 {
 func (layout *LayoutComponent) GetEventBus() *atoms.EventBus {
 	return layout.eventBus
 }
+
+// GetTypeName is matched by type selectors of style sheets.
+func (layout *LayoutComponent) GetTypeName() string {
+	return "Layout"
+}
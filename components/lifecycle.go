@@ -0,0 +1,143 @@
+package components
+
+// Lifecycle hooks are optional, a component implements only the ones it needs.
+// The app mounts the root component before the first layout and unmounts it when the window is closed.
+// Components added to (or removed from) a mounted container are mounted (or unmounted) right away.
+
+type MountHook interface {
+	OnMount()
+}
+
+type UnmountHook interface {
+	OnUnmount()
+}
+
+type BeforeLayoutHook interface {
+	OnBeforeLayout()
+}
+
+type AfterLayoutHook interface {
+	OnAfterLayout()
+}
+
+type FrameHook interface {
+	// OnFrame is called once per frame, before the layout is recalculated and the tree is rendered.
+	OnFrame(delta float32)
+}
+
+// LifecycleComponent is implemented by components which keep track of being mounted, usually with a ComponentLifecycle field.
+type LifecycleComponent interface {
+	GetLifecycle() *ComponentLifecycle
+}
+
+// ComponentLifecycle remembers if a component is mounted and releases its resources when it's unmounted.
+type ComponentLifecycle struct {
+	mounted  bool
+	releases []func()
+}
+
+func NewComponentLifecycle() ComponentLifecycle {
+	return ComponentLifecycle{
+		mounted:  false,
+		releases: []func(){},
+	}
+}
+
+func (lifecycle *ComponentLifecycle) IsMounted() bool {
+	return lifecycle.mounted
+}
+
+// ReleaseOnUnmount registers a function called when the component is unmounted, e.g. an atoms.UnsubscribeFunc returned by the event bus
+// or unloading of a texture. Functions are called in the reverse order of registration, and only once.
+func (lifecycle *ComponentLifecycle) ReleaseOnUnmount(release func()) {
+	lifecycle.releases = append(lifecycle.releases, release)
+}
+
+func (lifecycle *ComponentLifecycle) release() {
+	releases := lifecycle.releases
+	lifecycle.releases = []func(){}
+
+	for i := len(releases) - 1; i >= 0; i-- {
+		releases[i]()
+	}
+}
+
+// MountTree calls OnMount of the component and all of its descendants, parents before children.
+// Components which are already mounted are skipped.
+func MountTree(component Component) {
+	if lifecycleComponent, ok := component.(LifecycleComponent); ok {
+		lifecycle := lifecycleComponent.GetLifecycle()
+
+		if lifecycle.mounted {
+			return
+		}
+
+		lifecycle.mounted = true
+	}
+
+	if hook, ok := component.(MountHook); ok {
+		hook.OnMount()
+	}
+
+	if container, ok := component.(Container); ok {
+		for _, child := range container.GetChildren() {
+			MountTree(child)
+		}
+	}
+}
+
+// UnmountTree calls OnUnmount of the component and all of its descendants, children before parents,
+// and then releases everything registered with ReleaseOnUnmount.
+func UnmountTree(component Component) {
+	lifecycleComponent, tracksLifecycle := component.(LifecycleComponent)
+
+	if tracksLifecycle && !lifecycleComponent.GetLifecycle().mounted {
+		return
+	}
+
+	if container, ok := component.(Container); ok {
+		for _, child := range container.GetChildren() {
+			UnmountTree(child)
+		}
+	}
+
+	if hook, ok := component.(UnmountHook); ok {
+		hook.OnUnmount()
+	}
+
+	if tracksLifecycle {
+		lifecycle := lifecycleComponent.GetLifecycle()
+		lifecycle.mounted = false
+		lifecycle.release()
+	}
+}
+
+func CallBeforeLayoutHooks(root Component) {
+	Walk(root, func(component Component) bool {
+		if hook, ok := component.(BeforeLayoutHook); ok {
+			hook.OnBeforeLayout()
+		}
+
+		return true
+	})
+}
+
+func CallAfterLayoutHooks(root Component) {
+	Walk(root, func(component Component) bool {
+		if hook, ok := component.(AfterLayoutHook); ok {
+			hook.OnAfterLayout()
+		}
+
+		return true
+	})
+}
+
+func CallFrameHooks(root Component, delta float32) {
+	Walk(root, func(component Component) bool {
+		if hook, ok := component.(FrameHook); ok {
+			hook.OnFrame(delta)
+		}
+
+		return true
+	})
+}
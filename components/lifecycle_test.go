@@ -0,0 +1,114 @@
+package components
+
+import (
+	"slices"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// hookedComponent records calls of its lifecycle hooks into a log shared by the whole tree.
+type hookedComponent struct {
+	TextComponent
+
+	name      string
+	log       *[]string
+	lifecycle ComponentLifecycle
+}
+
+func newHookedComponent(eventBus *atoms.EventBus, name string, log *[]string) *hookedComponent {
+	return &hookedComponent{
+		TextComponent: *NewTextComponent(eventBus, name, "Roboto", 16, 0, rl.White),
+		name:          name,
+		log:           log,
+		lifecycle:     NewComponentLifecycle(),
+	}
+}
+
+func (comp *hookedComponent) GetLifecycle() *ComponentLifecycle {
+	return &comp.lifecycle
+}
+
+func (comp *hookedComponent) OnMount() {
+	*comp.log = append(*comp.log, "mount "+comp.name)
+
+	unsubscribe := comp.eventBus.ListenToEvent("test:ping", func(...interface{}) {
+		*comp.log = append(*comp.log, "ping "+comp.name)
+	})
+
+	comp.lifecycle.ReleaseOnUnmount(unsubscribe)
+}
+
+func (comp *hookedComponent) OnUnmount() {
+	*comp.log = append(*comp.log, "unmount "+comp.name)
+}
+
+func (comp *hookedComponent) OnFrame(delta float32) {
+	*comp.log = append(*comp.log, "frame "+comp.name)
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	expectLog := func(t *testing.T, log *[]string, expected []string) {
+		t.Helper()
+
+		if !slices.Equal(*log, expected) {
+			t.Errorf("Expected hooks %v, got %v", expected, *log)
+		}
+
+		*log = []string{}
+	}
+
+	t.Run("Tree is mounted parents first and unmounted children first", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		log := []string{}
+
+		layout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+		layout.AddChild(newHookedComponent(eventBus, "a", &log))
+
+		rectangle, _ := NewRectangleComponent(eventBus, newHookedComponent(eventBus, "b", &log), rl.Blank, 0)
+		layout.AddChild(rectangle)
+
+		expectLog(t, &log, []string{})
+
+		MountTree(layout)
+		MountTree(layout)
+		expectLog(t, &log, []string{"mount a", "mount b"})
+
+		CallFrameHooks(layout, 0.1)
+		expectLog(t, &log, []string{"frame a", "frame b"})
+
+		eventBus.DispatchEvent("test:ping")
+		expectLog(t, &log, []string{"ping a", "ping b"})
+
+		UnmountTree(layout)
+		UnmountTree(layout)
+		expectLog(t, &log, []string{"unmount a", "unmount b"})
+
+		// Listeners registered on mount are released on unmount.
+		eventBus.DispatchEvent("test:ping")
+		expectLog(t, &log, []string{})
+
+		if layout.GetLifecycle().IsMounted() || rectangle.GetLifecycle().IsMounted() {
+			t.Error("Expected the containers to be unmounted")
+		}
+	})
+
+	t.Run("Children added to a mounted tree are mounted", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		log := []string{}
+
+		layout, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+		rectangle, _ := NewRectangleComponent(eventBus, newHookedComponent(eventBus, "a", &log), rl.Blank, 0)
+		layout.AddChild(rectangle)
+
+		MountTree(layout)
+		expectLog(t, &log, []string{"mount a"})
+
+		layout.AddChild(newHookedComponent(eventBus, "b", &log))
+		expectLog(t, &log, []string{"mount b"})
+
+		rectangle.SetChild(newHookedComponent(eventBus, "c", &log))
+		expectLog(t, &log, []string{"unmount a", "mount c"})
+	})
+}
@@ -0,0 +1,247 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+
+	"domanscy.group/gui/components/atoms"
+)
+
+// Line breaking classes, a subset of the ones defined by the Unicode line breaking algorithm:
+// https://www.unicode.org/reports/tr14/
+const (
+	lineBreakClassAL = iota // ordinary alphabetic characters
+	lineBreakClassBK        // mandatory breaks
+	lineBreakClassCR
+	lineBreakClassSP // space
+	lineBreakClassGL // non-breaking glue, e.g. no-break space
+	lineBreakClassZW // zero width space
+	lineBreakClassCM // combining marks
+	lineBreakClassBA // break after, e.g. tab or soft hyphen
+	lineBreakClassHY // hyphen-minus
+	lineBreakClassID // ideographs, e.g. CJK characters, which allow a break between every two of them
+	lineBreakClassNS // nonstarters, e.g. small kana
+	lineBreakClassOP // opening punctuation
+	lineBreakClassCL // closing punctuation
+	lineBreakClassEX // exclamation and interrogation
+	lineBreakClassIS // infix numeric separators
+	lineBreakClassQU // quotation marks
+	lineBreakClassNU // numbers
+)
+
+const breakProhibited = 0
+const breakAllowed = 1
+const breakMandatory = 2
+
+const softHyphen = '\u00AD'
+const zeroWidthSpace = '\u200B'
+
+// TabSize is the width of a tab character in spaces.
+const TabSize = 4
+
+var nonstarters = map[rune]bool{
+	'ぁ': true, 'ぃ': true, 'ぅ': true, 'ぇ': true, 'ぉ': true, 'っ': true, 'ゃ': true, 'ゅ': true, 'ょ': true, 'ゎ': true,
+	'ァ': true, 'ィ': true, 'ゥ': true, 'ェ': true, 'ォ': true, 'ッ': true, 'ャ': true, 'ュ': true, 'ョ': true, 'ヮ': true,
+	'ヵ': true, 'ヶ': true, 'ー': true, '々': true, '・': true, 'ゝ': true, 'ゞ': true, 'ヽ': true, 'ヾ': true,
+}
+
+func getLineBreakClass(character rune) int {
+	switch character {
+	case '\n', '\v', '\f', '\u0085', '\u2028', '\u2029':
+		return lineBreakClassBK
+	case '\r':
+		return lineBreakClassCR
+	case ' ':
+		return lineBreakClassSP
+	case '\u00A0', '\u202F', '\u2007', '‑', '\u2060', '\uFEFF':
+		return lineBreakClassGL
+	case zeroWidthSpace:
+		return lineBreakClassZW
+	case '\u200D':
+		return lineBreakClassCM
+	case '\t', softHyphen, '|', '\u1680', '‐', '‒', '–', '\u3000':
+		return lineBreakClassBA
+	case '-':
+		return lineBreakClassHY
+	case '(', '[', '{', '「', '『', '【', '（', '〈', '《', '〔', '［', '｛':
+		return lineBreakClassOP
+	case ')', ']', '}', '」', '』', '】', '）', '〉', '》', '〕', '］', '｝', '、', '。', '，', '．':
+		return lineBreakClassCL
+	case '!', '?', '！', '？':
+		return lineBreakClassEX
+	case ',', '.', ':', ';':
+		return lineBreakClassIS
+	case '"', '\'', '«', '»', '‘', '’', '‚', '‛', '“', '”', '„', '‟':
+		return lineBreakClassQU
+	}
+
+	switch {
+	case character >= '\u2000' && character <= '\u200A' && character != '\u2007':
+		return lineBreakClassBA
+	case nonstarters[character]:
+		return lineBreakClassNS
+	case unicode.IsDigit(character):
+		return lineBreakClassNU
+	case unicode.In(character, unicode.Mn, unicode.Mc, unicode.Me):
+		return lineBreakClassCM
+	case isIdeographic(character):
+		return lineBreakClassID
+	}
+
+	return lineBreakClassAL
+}
+
+func isIdeographic(character rune) bool {
+	return (character >= 'ᄀ' && character <= 'ᅟ') || // Hangul Jamo
+		(character >= '⺀' && character <= '⿿') || // CJK radicals
+		(character >= '぀' && character <= 'ヿ') || // Hiragana and Katakana
+		(character >= '㐀' && character <= '䶿') || // CJK extension A
+		(character >= '一' && character <= '鿿') || // CJK unified ideographs
+		(character >= '가' && character <= '힯') || // Hangul syllables
+		(character >= '豈' && character <= '﫿') || // CJK compatibility ideographs
+		(character >= '！' && character <= '｠') || // fullwidth forms
+		(character >= '\U0001F300' && character <= '\U0001FAFF') || // emoji
+		(character >= '\U00020000' && character <= '\U0003FFFD') // CJK extensions B and later
+}
+
+func canBreakBetween(previousClass int, nextClass int, spacesBetween bool) bool {
+	// No break before closing punctuation, even after spaces.
+	if nextClass == lineBreakClassCL || nextClass == lineBreakClassEX || nextClass == lineBreakClassIS {
+		return false
+	}
+
+	// No break after opening punctuation, even before spaces.
+	if previousClass == lineBreakClassOP {
+		return false
+	}
+
+	if spacesBetween {
+		return true
+	}
+
+	switch {
+	case previousClass == lineBreakClassZW:
+		return true
+	case previousClass == lineBreakClassGL:
+		return false
+	case nextClass == lineBreakClassGL:
+		return previousClass == lineBreakClassBA || previousClass == lineBreakClassHY
+	case previousClass == lineBreakClassQU || nextClass == lineBreakClassQU:
+		return false
+	case nextClass == lineBreakClassBA || nextClass == lineBreakClassHY || nextClass == lineBreakClassNS:
+		return false
+	case previousClass == lineBreakClassHY && nextClass == lineBreakClassNU:
+		return false
+	case (previousClass == lineBreakClassAL || previousClass == lineBreakClassNU) && (nextClass == lineBreakClassAL || nextClass == lineBreakClassNU):
+		return false
+	case previousClass == lineBreakClassIS && nextClass == lineBreakClassNU:
+		return false
+	case (previousClass == lineBreakClassAL || previousClass == lineBreakClassNU) && nextClass == lineBreakClassOP:
+		return false
+	case previousClass == lineBreakClassCL && (nextClass == lineBreakClassAL || nextClass == lineBreakClassNU):
+		return false
+	}
+
+	return true
+}
+
+// findLineBreaks returns, for every character, whether the line can (or has to) be broken right before it.
+func findLineBreaks(runes []rune) []int {
+	breaks := make([]int, len(runes))
+
+	if len(runes) == 0 {
+		return breaks
+	}
+
+	classes := make([]int, len(runes))
+
+	for i, character := range runes {
+		classes[i] = getLineBreakClass(character)
+
+		// Combining marks take the class of the character they are attached to.
+		if classes[i] == lineBreakClassCM {
+			if i > 0 && classes[i-1] != lineBreakClassBK && classes[i-1] != lineBreakClassCR && classes[i-1] != lineBreakClassSP && classes[i-1] != lineBreakClassZW {
+				classes[i] = classes[i-1]
+			} else {
+				classes[i] = lineBreakClassAL
+			}
+		}
+	}
+
+	previousClass := classes[0]
+	spacesBetween := false
+
+	for i := 1; i < len(runes); i++ {
+		class := classes[i]
+
+		switch {
+		case classes[i-1] == lineBreakClassCR && runes[i] == '\n':
+			breaks[i] = breakProhibited
+		case classes[i-1] == lineBreakClassBK || classes[i-1] == lineBreakClassCR:
+			breaks[i] = breakMandatory
+		case class == lineBreakClassBK || class == lineBreakClassCR || class == lineBreakClassSP || class == lineBreakClassZW:
+			breaks[i] = breakProhibited
+		case getLineBreakClass(runes[i]) == lineBreakClassCM || runes[i-1] == '\u200D':
+			breaks[i] = breakProhibited
+		case canBreakBetween(previousClass, class, spacesBetween):
+			breaks[i] = breakAllowed
+		default:
+			breaks[i] = breakProhibited
+		}
+
+		if class == lineBreakClassSP {
+			spacesBetween = true
+		} else {
+			previousClass = class
+			spacesBetween = false
+		}
+	}
+
+	return breaks
+}
+
+func isMandatoryBreakCharacter(character rune) bool {
+	class := getLineBreakClass(character)
+
+	return class == lineBreakClassBK || class == lineBreakClassCR
+}
+
+func getCharacterWidth(font atoms.Font, character rune) float32 {
+	switch character {
+	case '\t':
+		return font.GlyphWidth(' ')*TabSize + font.Spacing()*(TabSize-1)
+	case softHyphen, zeroWidthSpace, '\u200D', '\u2060', '\uFEFF':
+		return 0
+	}
+
+	return font.GlyphWidth(character)
+}
+
+// normalizeLineText turns a line into text that can be measured and drawn glyph by glyph.
+// Tabs are expanded to spaces, invisible characters are dropped and a soft hyphen at the end of a broken line becomes a visible one.
+func normalizeLineText(runes []rune, endsParagraph bool) string {
+	end := len(runes)
+
+	for end > 0 && runes[end-1] == ' ' {
+		end--
+	}
+
+	var builder strings.Builder
+
+	for i, character := range runes[:end] {
+		switch character {
+		case '\t':
+			builder.WriteString(strings.Repeat(" ", TabSize))
+		case softHyphen:
+			if i == end-1 && !endsParagraph {
+				builder.WriteRune('-')
+			}
+		case zeroWidthSpace, '\u200D', '\u2060', '\uFEFF', '\r':
+			continue
+		default:
+			builder.WriteRune(character)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,426 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Markup describes a tree of elements in XML or JSON, e.g.
+//
+//	<layout direction="column" mainAxisAlignment="center">
+//		<rectangle id="card" backgroundColor="#202020" padding="8 16">
+//			<text font="Roboto" fontSize="24" color="white">Hello</text>
+//		</rectangle>
+//	</layout>
+//
+// The same tree in JSON is an object with "type", the attributes and "children":
+//
+//	{"type": "layout", "direction": "column", "children": [{"type": "text", "font": "Roboto", "text": "Hello"}]}
+
+var ErrInvalidMarkup = errors.New("invalid markup")
+
+// MarkupError points to the place in the markup, which couldn't be parsed.
+type MarkupError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (err *MarkupError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", err.Line, err.Column, err.Err)
+}
+
+func (err *MarkupError) Unwrap() error {
+	return err.Err
+}
+
+type markupPosition struct {
+	line   int
+	column int
+}
+
+func (position markupPosition) errorf(format string, args ...interface{}) error {
+	return &MarkupError{
+		Line:   position.line,
+		Column: position.column,
+		Err:    fmt.Errorf("%w: %s", ErrInvalidMarkup, fmt.Sprintf(format, args...)),
+	}
+}
+
+// markupPositions converts byte offsets of a source to lines and columns. Offsets are mostly requested in order,
+// so counting continues from the previous one, instead of starting from the beginning every time.
+type markupPositions struct {
+	source   string
+	offset   int
+	position markupPosition
+}
+
+func newMarkupPositions(source string) *markupPositions {
+	return &markupPositions{
+		source:   source,
+		offset:   0,
+		position: markupPosition{line: 1, column: 1},
+	}
+}
+
+func (positions *markupPositions) at(offset int) markupPosition {
+	offset = max(min(offset, len(positions.source)), 0)
+
+	if offset < positions.offset {
+		positions.offset = 0
+		positions.position = markupPosition{line: 1, column: 1}
+	}
+
+	for _, character := range []byte(positions.source[positions.offset:offset]) {
+		if character == '\n' {
+			positions.position.line++
+			positions.position.column = 1
+		} else {
+			positions.position.column++
+		}
+	}
+
+	positions.offset = offset
+
+	return positions.position
+}
+
+type markupAttribute struct {
+	name     string
+	value    string
+	position markupPosition
+}
+
+// markupNode is a parsed XML or JSON element, before its attributes are interpreted.
+type markupNode struct {
+	tag        string
+	position   markupPosition
+	attributes []markupAttribute
+	text       string
+	children   []*markupNode
+}
+
+// LoadMarkupFile parses a .xml or .json markup file.
+func LoadMarkupFile(path string) (*Element, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var element *Element
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		element, err = ParseXMLMarkup(file)
+	case ".json":
+		element, err = ParseJSONMarkup(file)
+	default:
+		return nil, fmt.Errorf("%w: unknown markup file extension of %s", ErrInvalidMarkup, path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return element, nil
+}
+
+func ParseXMLMarkup(reader io.Reader) (*Element, error) {
+	node, err := parseXMLMarkup(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildElement(node)
+}
+
+func ParseJSONMarkup(reader io.Reader) (*Element, error) {
+	node, err := parseJSONMarkup(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildElement(node)
+}
+
+// BuildComponent creates components described by the element tree, e.g. loaded from markup.
+func BuildComponent(eventBus *atoms.EventBus, element *Element) (Component, error) {
+	mounted, err := NewReconciler(eventBus, nil).create(element)
+	if err != nil {
+		return nil, err
+	}
+
+	return mounted.component, nil
+}
+
+var directionNames = map[string]int{
+	"column": DirectionColumn,
+	"row":    DirectionRow,
+}
+
+var alignmentNames = map[string]int{
+	"start":  AlignStart,
+	"center": AlignCenter,
+	"end":    AlignEnd,
+}
+
+var textAlignNames = map[string]int{
+	"left":    TextAlignLeft,
+	"center":  TextAlignCenter,
+	"right":   TextAlignRight,
+	"justify": TextAlignJustify,
+	"start":   TextAlignStart,
+	"end":     TextAlignEnd,
+}
+
+var fontStyleNames = map[string]int{
+	"normal": FontStyleNormal,
+	"italic": FontStyleItalic,
+}
+
+var colorNames = map[string]rl.Color{
+	"lightgray":  rl.LightGray,
+	"gray":       rl.Gray,
+	"darkgray":   rl.DarkGray,
+	"yellow":     rl.Yellow,
+	"gold":       rl.Gold,
+	"orange":     rl.Orange,
+	"pink":       rl.Pink,
+	"red":        rl.Red,
+	"maroon":     rl.Maroon,
+	"green":      rl.Green,
+	"lime":       rl.Lime,
+	"darkgreen":  rl.DarkGreen,
+	"skyblue":    rl.SkyBlue,
+	"blue":       rl.Blue,
+	"darkblue":   rl.DarkBlue,
+	"purple":     rl.Purple,
+	"violet":     rl.Violet,
+	"darkpurple": rl.DarkPurple,
+	"beige":      rl.Beige,
+	"brown":      rl.Brown,
+	"darkbrown":  rl.DarkBrown,
+	"white":      rl.White,
+	"black":      rl.Black,
+	"blank":      rl.Blank,
+	"magenta":    rl.Magenta,
+	"raywhite":   rl.RayWhite,
+}
+
+func buildElement(node *markupNode) (*Element, error) {
+	var element *Element
+
+	switch node.tag {
+	case "layout":
+		element = LayoutElement(DirectionColumn, AlignStart, AlignStart)
+
+		if strings.TrimSpace(node.text) != "" {
+			return nil, node.position.errorf("layout can't contain text")
+		}
+
+		for _, child := range node.children {
+			childElement, err := buildElement(child)
+			if err != nil {
+				return nil, err
+			}
+
+			element.children = append(element.children, childElement)
+		}
+	case "rectangle":
+		if strings.TrimSpace(node.text) != "" {
+			return nil, node.position.errorf("rectangle can't contain text")
+		}
+
+		if len(node.children) > 1 {
+			return nil, node.children[1].position.errorf("rectangle can have only one child")
+		}
+
+		var child *Element
+
+		if len(node.children) == 1 {
+			var err error
+
+			if child, err = buildElement(node.children[0]); err != nil {
+				return nil, err
+			}
+		}
+
+		element = RectangleElement(rl.Blank, 0, child)
+	case "text":
+		if len(node.children) > 0 {
+			return nil, node.children[0].position.errorf("text can't have children")
+		}
+
+		element = TextElement(strings.TrimSpace(node.text), "", 16, 0, rl.White)
+	default:
+		return nil, node.position.errorf("unknown element %q", node.tag)
+	}
+
+	for _, attribute := range node.attributes {
+		if err := applyAttribute(element, attribute); err != nil {
+			return nil, err
+		}
+	}
+
+	if element.kind == elementText && element.fontName == "" {
+		return nil, node.position.errorf("text requires the font attribute")
+	}
+
+	return element, nil
+}
+
+func applyAttribute(element *Element, attribute markupAttribute) error {
+	value := strings.TrimSpace(attribute.value)
+	position := attribute.position
+
+	var err error
+
+	switch {
+	case attribute.name == "id":
+		element.id = value
+	case attribute.name == "key":
+		element.key = value
+	case attribute.name == "tags":
+		element.tags = append(element.tags, strings.Fields(value)...)
+	case element.kind == elementLayout && attribute.name == "direction":
+		element.direction, err = parseName(directionNames, value)
+	case element.kind == elementLayout && attribute.name == "mainAxisAlignment":
+		element.mainAxisAlignment, err = parseName(alignmentNames, value)
+	case element.kind == elementLayout && attribute.name == "crossAxisAlignment":
+		element.crossAxisAlignment, err = parseName(alignmentNames, value)
+	case element.kind == elementRectangle && attribute.name == "backgroundColor":
+		element.backgroundColor, err = parseColor(value)
+	case element.kind == elementRectangle && attribute.name == "roundness":
+		element.roundness, err = parseNumber(value, 0)
+	case element.kind == elementRectangle && attribute.name == "padding":
+		element.padding, err = parsePadding(value)
+	case element.kind == elementText && attribute.name == "text":
+		element.text = attribute.value
+	case element.kind == elementText && attribute.name == "font":
+		element.fontName = value
+	case element.kind == elementText && attribute.name == "fontSize":
+		element.fontSize, err = parseNumber(value, 1)
+	case element.kind == elementText && attribute.name == "spacing":
+		element.spacing, err = parseNumber(value, 0)
+	case element.kind == elementText && attribute.name == "color":
+		element.color, err = parseColor(value)
+	case element.kind == elementText && attribute.name == "wrap":
+		element.wrapText, err = strconv.ParseBool(value)
+	case element.kind == elementText && attribute.name == "textAlign":
+		element.textAlign, err = parseName(textAlignNames, value)
+	case element.kind == elementText && attribute.name == "fontWeight":
+		element.fontWeight, err = parseFontWeight(value)
+	case element.kind == elementText && attribute.name == "fontStyle":
+		element.fontStyle, err = parseName(fontStyleNames, value)
+	default:
+		return position.errorf("unknown attribute %q", attribute.name)
+	}
+
+	if err != nil {
+		return position.errorf("invalid value of %s: %v", attribute.name, err)
+	}
+
+	return nil
+}
+
+func parseName(names map[string]int, value string) (int, error) {
+	if result, ok := names[value]; ok {
+		return result, nil
+	}
+
+	return 0, fmt.Errorf("unknown value %q", value)
+}
+
+func parseNumber(value string, min float32) (float32, error) {
+	number, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	if float32(number) < min {
+		return 0, fmt.Errorf("%s is less than %g", value, min)
+	}
+
+	return float32(number), nil
+}
+
+func parseFontWeight(value string) (int, error) {
+	weight, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if weight < FontWeightThin || weight > FontWeightBlack || weight%100 != 0 {
+		return 0, fmt.Errorf("%d isn't one of 100, 200, ..., 900", weight)
+	}
+
+	return weight, nil
+}
+
+// parseColor reads a color name or a hex color, #rrggbb or #rrggbbaa.
+func parseColor(value string) (rl.Color, error) {
+	if color, ok := colorNames[strings.ToLower(value)]; ok {
+		return color, nil
+	}
+
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return rl.Blank, fmt.Errorf("unknown color %q", value)
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	rgba, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rl.Blank, fmt.Errorf("unknown color %q", value)
+	}
+
+	return rl.NewColor(uint8(rgba>>24), uint8(rgba>>16), uint8(rgba>>8), uint8(rgba)), nil
+}
+
+// parsePadding reads one to four numbers in the CSS order: top, right, bottom, left.
+func parsePadding(value string) (atoms.ClockValues, error) {
+	padding := atoms.NewClockValues()
+	fields := strings.Fields(value)
+	values := make([]float32, len(fields))
+
+	for i, field := range fields {
+		number, err := parseNumber(field, 0)
+		if err != nil {
+			return padding, err
+		}
+
+		values[i] = number
+	}
+
+	switch len(values) {
+	case 1:
+		values = []float32{values[0], values[0], values[0], values[0]}
+	case 2:
+		values = []float32{values[0], values[1], values[0], values[1]}
+	case 3:
+		values = []float32{values[0], values[1], values[2], values[1]}
+	case 4:
+	default:
+		return padding, fmt.Errorf("expected 1 to 4 numbers, got %d", len(values))
+	}
+
+	padding.SetTop(values[0])
+	padding.SetRight(values[1])
+	padding.SetBottom(values[2])
+	padding.SetLeft(values[3])
+
+	return padding, nil
+}
@@ -0,0 +1,166 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonMarkupParser walks the tokens of a JSON document, remembering where each of them starts.
+type jsonMarkupParser struct {
+	data      []byte
+	positions *markupPositions
+	decoder   *json.Decoder
+}
+
+func parseJSONMarkup(reader io.Reader) (*markupNode, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &jsonMarkupParser{
+		data:      data,
+		positions: newMarkupPositions(string(data)),
+		decoder:   json.NewDecoder(bytes.NewReader(data)),
+	}
+
+	parser.decoder.UseNumber()
+
+	node, err := parser.parseNode()
+	if err != nil {
+		return nil, err
+	}
+
+	if position, token, err := parser.next(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, position.errorf("unexpected %v after the root element", token)
+	}
+
+	return node, nil
+}
+
+// positionAt converts a byte offset to a line and a column, skipping separators preceding the token.
+func (parser *jsonMarkupParser) positionAt(offset int64) markupPosition {
+	for offset < int64(len(parser.data)) && bytes.IndexByte([]byte(" \t\r\n,:"), parser.data[offset]) != -1 {
+		offset++
+	}
+
+	return parser.positions.at(int(offset))
+}
+
+func (parser *jsonMarkupParser) next() (markupPosition, json.Token, error) {
+	position := parser.positionAt(parser.decoder.InputOffset())
+
+	token, err := parser.decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return position, nil, err
+	}
+
+	if err != nil {
+		var syntaxError *json.SyntaxError
+
+		if errors.As(err, &syntaxError) {
+			// The offset of a syntax error is right after the invalid character.
+			position = parser.positionAt(max(syntaxError.Offset-1, 0))
+		}
+
+		return position, nil, position.errorf("%v", err)
+	}
+
+	return position, token, nil
+}
+
+func (parser *jsonMarkupParser) parseNode() (*markupNode, error) {
+	position, token, err := parser.next()
+	if errors.Is(err, io.EOF) {
+		return nil, position.errorf("unexpected end of markup")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if token != json.Delim('{') {
+		return nil, position.errorf("expected an element object, got %v", token)
+	}
+
+	node := &markupNode{
+		position:   position,
+		attributes: []markupAttribute{},
+		children:   []*markupNode{},
+	}
+
+	for parser.decoder.More() {
+		keyPosition, key, err := parser.next()
+		if err != nil {
+			return nil, err
+		}
+
+		name := key.(string)
+
+		if name == "children" {
+			if err := parser.parseChildren(node); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		valuePosition, value, err := parser.next()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := value.(json.Delim); ok || value == nil {
+			return nil, valuePosition.errorf("value of %s has to be a string, a number or a boolean", name)
+		}
+
+		switch name {
+		case "type":
+			node.tag = fmt.Sprint(value)
+		case "text":
+			node.text = fmt.Sprint(value)
+		default:
+			node.attributes = append(node.attributes, markupAttribute{name, fmt.Sprint(value), keyPosition})
+		}
+	}
+
+	// The closing brace.
+	if _, _, err := parser.next(); err != nil {
+		return nil, err
+	}
+
+	if node.tag == "" {
+		return nil, position.errorf("element has no type")
+	}
+
+	return node, nil
+}
+
+func (parser *jsonMarkupParser) parseChildren(node *markupNode) error {
+	position, token, err := parser.next()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('[') {
+		return position.errorf("children have to be an array")
+	}
+
+	for parser.decoder.More() {
+		child, err := parser.parseNode()
+		if err != nil {
+			return err
+		}
+
+		node.children = append(node.children, child)
+	}
+
+	_, _, err = parser.next()
+
+	return err
+}
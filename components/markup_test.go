@@ -0,0 +1,107 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const xmlMarkup = `<layout id="root" direction="row" mainAxisAlignment="center">
+	<rectangle id="card" backgroundColor="#10203040" padding="4 8" tags="card primary">
+		<text id="title" font="Roboto" fontSize="24" color="red" fontWeight="700" textAlign="center">
+			Hello
+		</text>
+	</rectangle>
+	<text font="Roboto" text="World" wrap="true"/>
+</layout>`
+
+const jsonMarkup = `{
+	"type": "layout", "id": "root", "direction": "row", "mainAxisAlignment": "center",
+	"children": [
+		{
+			"type": "rectangle", "id": "card", "backgroundColor": "#10203040", "padding": "4 8", "tags": "card primary",
+			"children": [
+				{"type": "text", "id": "title", "font": "Roboto", "fontSize": 24, "color": "red", "fontWeight": 700, "textAlign": "center", "text": "Hello"}
+			]
+		},
+		{"type": "text", "font": "Roboto", "text": "World", "wrap": true}
+	]
+}`
+
+func TestMarkup(t *testing.T) {
+	formats := map[string]func(string) (*Element, error){
+		"XML": func(markup string) (*Element, error) {
+			return ParseXMLMarkup(strings.NewReader(markup))
+		},
+		"JSON": func(markup string) (*Element, error) {
+			return ParseJSONMarkup(strings.NewReader(markup))
+		},
+	}
+
+	for name, markup := range map[string]string{"XML": xmlMarkup, "JSON": jsonMarkup} {
+		t.Run(name+" markup is built into components", func(t *testing.T) {
+			element, err := formats[name](markup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			root, err := BuildComponent(atoms.NewEventBus(), element)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			card, ok := FindByID(root, "card").(*RectangleComponent)
+			if !ok {
+				t.Fatalf("Expected a rectangle with ID card, got %v", FindByID(root, "card"))
+			}
+
+			if card.GetBackgroundColor() != rl.NewColor(0x10, 0x20, 0x30, 0x40) || card.GetPaddingTop() != 4 || card.GetPaddingLeft() != 8 || !card.HasTag("primary") {
+				t.Errorf("Unexpected rectangle properties: %v %v", card.GetBackgroundColor(), card.GetTags())
+			}
+
+			title, ok := FindByID(root, "title").(*TextComponent)
+			if !ok || title.GetText() != "Hello" || title.GetColor() != rl.Red || title.fontWeight != FontWeightBold || title.fontSize != 24 {
+				t.Errorf("Unexpected title: %+v", title)
+			}
+
+			texts := FindByType[*TextComponent](root)
+			if len(texts) != 2 || texts[1].GetText() != "World" || !texts[1].wrapText {
+				t.Errorf("Expected the second text to be World, got %v", texts)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		format string
+		markup string
+		line   int
+		column int
+	}{
+		{"XML", "<layout>\n\t<rectangle>\n\t\t<button/>\n\t</rectangle>\n</layout>", 3, 3},
+		{"XML", "<layout>\n\t<text font=\"Roboto\" fontSize=\"big\"/>\n</layout>", 2, 22},
+		{"XML", "<layout>\n\t<text\n\t\tfont=\"Roboto\"\n\t\tcolor='nope'/>\n</layout>", 4, 3},
+		{"XML", "<layout>\n\t<text font=\"Roboto\">\n</layout>", 3, 1},
+		{"JSON", "{\n\t\"type\": \"layout\",\n\t\"direction\": \"diagonal\"\n}", 3, 2},
+		{"JSON", "{\n\t\"type\": \"layout\",\n\t\"children\": [{\"type\": \"text\"}]\n}", 3, 15},
+		{"JSON", "{\n\t\"type\": \"layout\"\n\t\"children\": []\n}", 3, 2},
+	}
+
+	for _, errorCase := range errorCases {
+		t.Run(errorCase.format+" errors have positions", func(t *testing.T) {
+			_, err := formats[errorCase.format](errorCase.markup)
+
+			var markupError *MarkupError
+
+			if !errors.As(err, &markupError) || !errors.Is(err, ErrInvalidMarkup) {
+				t.Fatalf("Expected a markup error, got %v", err)
+			}
+
+			if markupError.Line != errorCase.line || markupError.Column != errorCase.column {
+				t.Errorf("Expected the error at %d:%d, got %v", errorCase.line, errorCase.column, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package components
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+func parseXMLMarkup(reader io.Reader) (*markupNode, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	positions := newMarkupPositions(string(data))
+
+	var root *markupNode
+	stack := []*markupNode{}
+
+	for {
+		// The position is taken before the token, so it points at its first character.
+		line, column := decoder.InputPos()
+		position := markupPosition{line, column}
+		start := decoder.InputOffset()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, position.errorf("%v", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &markupNode{
+				tag:        token.Name.Local,
+				position:   position,
+				attributes: []markupAttribute{},
+				children:   []*markupNode{},
+			}
+
+			offsets := xmlAttributeOffsets(data[start:decoder.InputOffset()])
+
+			for i, attribute := range token.Attr {
+				attributePosition := position
+
+				if len(offsets) == len(token.Attr) {
+					attributePosition = positions.at(int(start) + offsets[i])
+				}
+
+				node.attributes = append(node.attributes, markupAttribute{attribute.Name.Local, attribute.Value, attributePosition})
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root != nil {
+				return nil, position.errorf("markup can have only one root element")
+			} else {
+				root = node
+			}
+
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(token)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, markupPosition{1, 1}.errorf("markup has no root element")
+	}
+
+	return root, nil
+}
+
+// xmlAttributeOffsets returns where the attributes of a start tag begin, relative to the tag.
+// encoding/xml reports only the position of the whole tag, which is already validated by it here.
+func xmlAttributeOffsets(tag []byte) []int {
+	offsets := []int{}
+
+	// Attributes start after the element name.
+	i := bytes.IndexAny(tag, " \t\r\n")
+	if i == -1 {
+		return offsets
+	}
+
+	for i < len(tag) {
+		for i < len(tag) && bytes.IndexByte([]byte(" \t\r\n"), tag[i]) != -1 {
+			i++
+		}
+
+		if i >= len(tag) || tag[i] == '/' || tag[i] == '>' {
+			break
+		}
+
+		offsets = append(offsets, i)
+
+		// The name and = are followed by a value in single or double quotes.
+		quote := bytes.IndexAny(tag[i:], "\"'")
+		if quote == -1 {
+			break
+		}
+
+		i += quote
+
+		length := bytes.IndexByte(tag[i+1:], tag[i])
+		if length == -1 {
+			break
+		}
+
+		i += length + 2
+	}
+
+	return offsets
+}
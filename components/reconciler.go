@@ -0,0 +1,398 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	"domanscy.group/gui/components/atoms"
+)
+
+// mountedElement is an element rendered last time, together with the component created for it.
+type mountedElement struct {
+	element   *Element
+	component Component
+	children  []*mountedElement
+}
+
+// Reconciler keeps the children of a host layout in sync with element trees. Every update is compared with the previous one,
+// and only the differences are applied to the existing components, so their state (e.g. mounted listeners) survives.
+// To render the UI as a function of signals, call Update from an effect, see atoms.NewEffect.
+type Reconciler struct {
+	eventBus *atoms.EventBus
+	host     *LayoutComponent
+	children []*mountedElement
+}
+
+func NewReconciler(eventBus *atoms.EventBus, host *LayoutComponent) *Reconciler {
+	return &Reconciler{
+		eventBus: eventBus,
+		host:     host,
+		children: []*mountedElement{},
+	}
+}
+
+// Update makes the children of the host match the elements. The whole tree is validated first,
+// so if a property is invalid, the error is returned and none of the components is changed.
+func (reconciler *Reconciler) Update(elements ...*Element) error {
+	for _, element := range elements {
+		if err := validateElement(element); err != nil {
+			return err
+		}
+	}
+
+	children, err := reconciler.reconcileChildren(reconciler.host, reconciler.children, elements)
+	reconciler.children = children
+
+	return err
+}
+
+func (reconciler *Reconciler) reconcileChildren(layout *LayoutComponent, previous []*mountedElement, elements []*Element) ([]*mountedElement, error) {
+	next := make([]*mountedElement, len(elements))
+	used := make([]bool, len(previous))
+
+	keyed := map[string]int{}
+	unkeyed := []int{}
+
+	for i, mounted := range previous {
+		if mounted.element.key != "" {
+			keyed[mounted.element.key] = i
+		} else {
+			unkeyed = append(unkeyed, i)
+		}
+	}
+
+	for i, element := range elements {
+		match := -1
+
+		if element.key != "" {
+			if index, ok := keyed[element.key]; ok && !used[index] {
+				match = index
+			}
+		} else if len(unkeyed) > 0 {
+			match = unkeyed[0]
+			unkeyed = unkeyed[1:]
+		}
+
+		var err error
+
+		if match != -1 && previous[match].element.kind == element.kind {
+			used[match] = true
+			next[i], err = reconciler.patch(previous[match], element)
+		} else {
+			next[i], err = reconciler.create(element)
+		}
+
+		if err != nil {
+			return previous, err
+		}
+	}
+
+	// Children are rearranged only when all of them were created and patched.
+	for i, mounted := range previous {
+		if !used[i] {
+			if err := layout.RemoveChild(mounted.component); err != nil {
+				return next, err
+			}
+		}
+	}
+
+	for i, mounted := range next {
+		children := layout.GetChildren()
+
+		if i < len(children) && children[i] == mounted.component {
+			continue
+		}
+
+		var err error
+
+		if slices.Contains(children, mounted.component) {
+			err = layout.MoveChild(mounted.component, i)
+		} else {
+			err = layout.InsertChild(i, mounted.component)
+		}
+
+		if err != nil {
+			return next, err
+		}
+	}
+
+	return next, nil
+}
+
+func (reconciler *Reconciler) create(element *Element) (*mountedElement, error) {
+	mounted := &mountedElement{
+		element:  element,
+		children: []*mountedElement{},
+	}
+
+	for _, child := range element.children {
+		mountedChild, err := reconciler.create(child)
+		if err != nil {
+			return nil, err
+		}
+
+		mounted.children = append(mounted.children, mountedChild)
+	}
+
+	// Properties are patched starting from an empty element of the same kind.
+	initial := &Element{kind: element.kind, tags: []string{}}
+
+	switch element.kind {
+	case elementLayout:
+		layout, err := NewLayoutComponent(reconciler.eventBus, element.direction, element.mainAxisAlignment, element.crossAxisAlignment)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range mounted.children {
+			layout.AddChild(child.component)
+		}
+
+		initial.direction = element.direction
+		initial.mainAxisAlignment = element.mainAxisAlignment
+		initial.crossAxisAlignment = element.crossAxisAlignment
+
+		mounted.component = layout
+	case elementRectangle:
+		var child Component
+
+		if len(mounted.children) > 0 {
+			child = mounted.children[0].component
+		}
+
+		rectangle, err := NewRectangleComponent(reconciler.eventBus, child, element.backgroundColor, element.roundness)
+		if err != nil {
+			return nil, err
+		}
+
+		initial.backgroundColor = element.backgroundColor
+		initial.roundness = element.roundness
+
+		mounted.component = rectangle
+	case elementText:
+		mounted.component = NewTextComponent(reconciler.eventBus, element.text, element.fontName, element.fontSize, element.spacing, element.color)
+
+		initial.text = element.text
+		initial.fontName = element.fontName
+		initial.spacing = element.spacing
+		initial.color = element.color
+		initial.textAlign = TextAlignStart
+		initial.fontWeight = FontWeightNormal
+		initial.fontStyle = FontStyleNormal
+	}
+
+	if err := patchProperties(mounted.component, initial, element); err != nil {
+		return nil, err
+	}
+
+	return mounted, nil
+}
+
+func (reconciler *Reconciler) patch(mounted *mountedElement, element *Element) (*mountedElement, error) {
+	if err := patchProperties(mounted.component, mounted.element, element); err != nil {
+		return nil, err
+	}
+
+	switch component := mounted.component.(type) {
+	case *LayoutComponent:
+		children, err := reconciler.reconcileChildren(component, mounted.children, element.children)
+		mounted.children = children
+
+		if err != nil {
+			return nil, err
+		}
+	case *RectangleComponent:
+		if err := reconciler.reconcileRectangleChild(component, mounted, element); err != nil {
+			return nil, err
+		}
+	}
+
+	mounted.element = element
+
+	return mounted, nil
+}
+
+func (reconciler *Reconciler) reconcileRectangleChild(rectangle *RectangleComponent, mounted *mountedElement, element *Element) error {
+	if len(element.children) == 0 {
+		if len(mounted.children) > 0 {
+			rectangle.RemoveChild()
+			mounted.children = []*mountedElement{}
+		}
+
+		return nil
+	}
+
+	child := element.children[0]
+
+	if len(mounted.children) > 0 {
+		previous := mounted.children[0]
+
+		if previous.element.kind == child.kind && previous.element.key == child.key {
+			_, err := reconciler.patch(previous, child)
+			return err
+		}
+	}
+
+	created, err := reconciler.create(child)
+	if err != nil {
+		return err
+	}
+
+	rectangle.SetChild(created.component)
+	mounted.children = []*mountedElement{created}
+
+	return nil
+}
+
+// validateElement checks the properties of the element and its descendants the same way their setters do.
+func validateElement(element *Element) error {
+	switch element.kind {
+	case elementLayout:
+		if element.direction != DirectionColumn && element.direction != DirectionRow {
+			return fmt.Errorf("%w: unknown value for direction property: %d", ErrInvalidProperty, element.direction)
+		}
+
+		if element.mainAxisAlignment != AlignCenter && element.mainAxisAlignment != AlignStart && element.mainAxisAlignment != AlignEnd {
+			return fmt.Errorf("%w: unknown value for mainAxisAlignment property: %d", ErrInvalidProperty, element.mainAxisAlignment)
+		}
+
+		if element.crossAxisAlignment != AlignCenter && element.crossAxisAlignment != AlignStart && element.crossAxisAlignment != AlignEnd {
+			return fmt.Errorf("%w: unknown value for crossAxisAlignment property: %d", ErrInvalidProperty, element.crossAxisAlignment)
+		}
+	case elementRectangle:
+		if element.roundness < 0 {
+			return fmt.Errorf("%w: roundness can't be less than 0", ErrInvalidProperty)
+		}
+	case elementText:
+		if element.fontSize <= 0 {
+			return fmt.Errorf("%w: fontSize has to be greater than 0, got %f", ErrInvalidProperty, element.fontSize)
+		}
+
+		if element.textAlign != TextAlignLeft && element.textAlign != TextAlignCenter && element.textAlign != TextAlignRight && element.textAlign != TextAlignJustify && element.textAlign != TextAlignStart && element.textAlign != TextAlignEnd {
+			return fmt.Errorf("%w: unknown value for textAlign property: %d", ErrInvalidProperty, element.textAlign)
+		}
+
+		if element.fontWeight < FontWeightThin || element.fontWeight > FontWeightBlack || element.fontWeight%100 != 0 {
+			return fmt.Errorf("%w: unknown value for fontWeight property: %d", ErrInvalidProperty, element.fontWeight)
+		}
+
+		if element.fontStyle != FontStyleNormal && element.fontStyle != FontStyleItalic {
+			return fmt.Errorf("%w: unknown value for fontStyle property: %d", ErrInvalidProperty, element.fontStyle)
+		}
+	}
+
+	for _, child := range element.children {
+		if err := validateElement(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchProperties calls setters only for the properties which differ, so unchanged elements don't schedule a relayout.
+func patchProperties(component Component, previous *Element, element *Element) error {
+	switch component := component.(type) {
+	case *LayoutComponent:
+		patchNode(&component.ComponentNode, previous, element)
+
+		if previous.direction != element.direction {
+			if err := component.SetDirection(element.direction); err != nil {
+				return err
+			}
+		}
+
+		if previous.mainAxisAlignment != element.mainAxisAlignment {
+			if err := component.SetMainAxisAlignment(element.mainAxisAlignment); err != nil {
+				return err
+			}
+		}
+
+		if previous.crossAxisAlignment != element.crossAxisAlignment {
+			if err := component.SetCrossAxisAlignment(element.crossAxisAlignment); err != nil {
+				return err
+			}
+		}
+	case *RectangleComponent:
+		patchNode(&component.ComponentNode, previous, element)
+
+		if previous.roundness != element.roundness {
+			if err := component.SetRoundness(element.roundness); err != nil {
+				return err
+			}
+		}
+
+		if previous.backgroundColor != element.backgroundColor {
+			component.SetBackgroundColor(element.backgroundColor)
+		}
+
+		if previous.padding != element.padding {
+			component.SetPaddingTop(element.padding.Top())
+			component.SetPaddingRight(element.padding.Right())
+			component.SetPaddingBottom(element.padding.Bottom())
+			component.SetPaddingLeft(element.padding.Left())
+		}
+	case *TextComponent:
+		patchNode(&component.ComponentNode, previous, element)
+
+		if previous.text != element.text {
+			component.SetText(element.text)
+		}
+
+		if previous.fontName != element.fontName {
+			component.SetFontName(element.fontName)
+		}
+
+		if previous.fontSize != element.fontSize {
+			if err := component.SetFontSize(element.fontSize); err != nil {
+				return err
+			}
+		}
+
+		if previous.spacing != element.spacing {
+			component.SetSpacing(element.spacing)
+		}
+
+		if previous.color != element.color {
+			component.SetColor(element.color)
+		}
+
+		if previous.wrapText != element.wrapText {
+			component.SetWrapText(element.wrapText)
+		}
+
+		if previous.textAlign != element.textAlign {
+			if err := component.SetTextAlign(element.textAlign); err != nil {
+				return err
+			}
+		}
+
+		if previous.fontWeight != element.fontWeight {
+			if err := component.SetFontWeight(element.fontWeight); err != nil {
+				return err
+			}
+		}
+
+		if previous.fontStyle != element.fontStyle {
+			if err := component.SetFontStyle(element.fontStyle); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func patchNode(node *ComponentNode, previous *Element, element *Element) {
+	node.SetID(element.id)
+
+	for _, tag := range previous.tags {
+		if !slices.Contains(element.tags, tag) {
+			node.RemoveTag(tag)
+		}
+	}
+
+	for _, tag := range element.tags {
+		node.AddTag(tag)
+	}
+}
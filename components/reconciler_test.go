@@ -0,0 +1,146 @@
+package components
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestReconciler(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	recalculations := 0
+	atoms.Subscribe(eventBus, ScheduleRecalculationEvent, func(struct{}) {
+		recalculations++
+	})
+
+	host, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	reconciler := NewReconciler(eventBus, host)
+
+	list := func(items ...string) *Element {
+		children := []*Element{}
+
+		for _, item := range items {
+			children = append(children, TextElement(item, "Roboto", 16, 0, rl.White).WithKey(item))
+		}
+
+		return LayoutElement(DirectionColumn, AlignStart, AlignStart, children...).WithID("list")
+	}
+
+	texts := func() []string {
+		result := []string{}
+
+		for _, text := range FindByType[*TextComponent](host) {
+			result = append(result, text.GetText())
+		}
+
+		return result
+	}
+
+	t.Run("First update creates components", func(t *testing.T) {
+		err := reconciler.Update(
+			RectangleElement(rl.Blue, 0, TextElement("Title", "Roboto", 32, 0, rl.White)).WithPadding(4, 4, 4, 4),
+			list("a", "b", "c"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(host.GetChildren()) != 2 || FindByID(host, "list") == nil {
+			t.Fatalf("Expected a rectangle and a list, got %v", host.GetChildren())
+		}
+
+		if rectangle := host.GetChildren()[0].(*RectangleComponent); rectangle.GetPaddingLeft() != 4 {
+			t.Errorf("Expected padding 4, got %f", rectangle.GetPaddingLeft())
+		}
+	})
+
+	t.Run("Unchanged elements don't touch components", func(t *testing.T) {
+		recalculations = 0
+
+		err := reconciler.Update(
+			RectangleElement(rl.Blue, 0, TextElement("Title", "Roboto", 32, 0, rl.White)).WithPadding(4, 4, 4, 4),
+			list("a", "b", "c"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if recalculations != 0 {
+			t.Errorf("Expected no relayout, got %d", recalculations)
+		}
+	})
+
+	t.Run("Keyed children are kept when reordered", func(t *testing.T) {
+		before := FindByType[*TextComponent](FindByID(host, "list"))
+
+		err := reconciler.Update(
+			RectangleElement(rl.Red, 0, TextElement("New title", "Roboto", 32, 0, rl.White)).WithPadding(4, 4, 4, 4),
+			list("c", "a", "d"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"New title", "c", "a", "d"}
+		if got := texts(); !slices.Equal(got, expected) {
+			t.Errorf("Expected texts %v, got %v", expected, got)
+		}
+
+		after := FindByType[*TextComponent](FindByID(host, "list"))
+
+		if after[0] != before[2] || after[1] != before[0] {
+			t.Error("Expected the components of keyed children to be moved, not recreated")
+		}
+
+		if before[1].GetParent() != nil {
+			t.Error("Expected the removed child to be detached")
+		}
+	})
+
+	t.Run("Elements of another kind replace the component", func(t *testing.T) {
+		previous := host.GetChildren()[0]
+
+		if err := reconciler.Update(TextElement("Title", "Roboto", 32, 0, rl.White), list()); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := host.GetChildren()[0].(*TextComponent); !ok || previous.(*RectangleComponent).GetParent() != nil {
+			t.Errorf("Expected the rectangle to be replaced by a text, got %v", host.GetChildren())
+		}
+
+		if len(FindByID(host, "list").(*LayoutComponent).GetChildren()) != 0 {
+			t.Error("Expected the list to be empty")
+		}
+	})
+
+	t.Run("Invalid properties are reported", func(t *testing.T) {
+		err := reconciler.Update(TextElement("Title", "Roboto", 32, 0, rl.White).WithTextAlign(42), list())
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty, got %v", err)
+		}
+
+		if len(host.GetChildren()) != 2 {
+			t.Errorf("Expected the children to stay, got %v", host.GetChildren())
+		}
+	})
+
+	t.Run("Invalid elements don't change any component", func(t *testing.T) {
+		title := host.GetChildren()[0].(*TextComponent)
+
+		err := reconciler.Update(
+			TextElement("Changed title", "Roboto", 32, 0, rl.White),
+			LayoutElement(DirectionColumn, AlignStart, AlignStart, TextElement("Item", "Roboto", 0, 0, rl.White)),
+		)
+		if !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("Expected ErrInvalidProperty, got %v", err)
+		}
+
+		if title.GetText() != "Title" {
+			t.Errorf("Expected the title not to be patched, got %q", title.GetText())
+		}
+	})
+}
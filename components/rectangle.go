@@ -1,15 +1,20 @@
 package components
 
 import (
+	"fmt"
+
 	"domanscy.group/gui/components/atoms"
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 type RectangleComponent struct {
+	ComponentNode
+
 	eventBus *atoms.EventBus
 	child    Component
 
-	position ComponentPosition
+	position  ComponentPosition
+	lifecycle ComponentLifecycle
 
 	size rl.Vector2
 
@@ -19,25 +24,85 @@ type RectangleComponent struct {
 	roundness float32
 }
 
-func NewRectangleComponent(eventBus *atoms.EventBus, child Component, backgroundColor rl.Color, roundness float32) *RectangleComponent {
+func NewRectangleComponent(eventBus *atoms.EventBus, child Component, backgroundColor rl.Color, roundness float32) (*RectangleComponent, error) {
 	if roundness < 0 {
-		panic("Roundness can't be less than 0.")
+		return nil, fmt.Errorf("%w: roundness can't be less than 0", ErrInvalidProperty)
 	}
 
-	return &RectangleComponent{
-		eventBus: eventBus,
-		child:    child,
-		position: NewComponentPosition(),
+	rectangle := &RectangleComponent{
+		ComponentNode: NewComponentNode(),
+
+		eventBus:  eventBus,
+		child:     child,
+		position:  NewComponentPosition(),
+		lifecycle: NewComponentLifecycle(),
 
 		size:            rl.Vector2Zero(),
 		backgroundColor: backgroundColor,
 		padding:         atoms.NewClockValues(),
 		roundness:       roundness,
 	}
+
+	if child != nil {
+		detachFromParent(child)
+		setParent(child, rectangle)
+	}
+
+	return rectangle, nil
 }
 
+// SetChild replaces the child, if the rectangle is mounted the previous child is unmounted and the new one mounted.
+// A rectangle without a child (nil) is as big as its padding.
 func (rec *RectangleComponent) SetChild(child Component) {
+	if child == rec.child {
+		return
+	}
+
+	if child != nil {
+		detachFromParent(child)
+	}
+
+	if rec.child != nil {
+		setParent(rec.child, nil)
+
+		if rec.lifecycle.IsMounted() {
+			UnmountTree(rec.child)
+		}
+	}
+
 	rec.child = child
+
+	if rec.child != nil {
+		setParent(rec.child, rec)
+
+		if rec.lifecycle.IsMounted() {
+			MountTree(rec.child)
+		}
+	}
+
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+func (rec *RectangleComponent) RemoveChild() {
+	rec.SetChild(nil)
+}
+
+func (rec *RectangleComponent) detachChild(child Component) {
+	if rec.child == child {
+		rec.SetChild(nil)
+	}
+}
+
+func (rec *RectangleComponent) GetChildren() []Component {
+	if rec.child == nil {
+		return []Component{}
+	}
+
+	return []Component{rec.child}
+}
+
+func (rec *RectangleComponent) GetLifecycle() *ComponentLifecycle {
+	return &rec.lifecycle
 }
 
 func (rec *RectangleComponent) getChildPositionOffset() rl.Vector2 {
@@ -50,16 +115,20 @@ func (rec *RectangleComponent) getChildPositionOffset() rl.Vector2 {
 func (rec *RectangleComponent) SetPosition(pos rl.Vector2) {
 	rec.position.Position = pos
 
-	rec.child.SetPositionOffset(rec.getChildPositionOffset())
+	if rec.child != nil {
+		rec.child.SetPositionOffset(rec.getChildPositionOffset())
+	}
 }
 
 func (rec *RectangleComponent) SetPositionOffset(offset rl.Vector2) {
 	rec.position.Offset = offset
 
-	rec.child.SetPositionOffset(rec.getChildPositionOffset())
+	if rec.child != nil {
+		rec.child.SetPositionOffset(rec.getChildPositionOffset())
+	}
 }
 
-func (rec *RectangleComponent) Render(getFont GetFontCallback) {
+func (rec *RectangleComponent) Render(getFont GetFontCallback) error {
 	position := rec.GetPosition()
 
 	if rec.roundness != 0 {
@@ -75,22 +144,40 @@ func (rec *RectangleComponent) Render(getFont GetFontCallback) {
 		rl.DrawRectangle(int32(position.X), int32(position.Y), int32(rec.size.X), int32(rec.size.Y), rec.backgroundColor)
 	}
 
-	rec.child.Render(getFont)
+	if rec.child == nil {
+		return nil
+	}
+
+	return rec.child.Render(getFont)
 }
 
-func (rec *RectangleComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) rl.Vector2 {
-	childSize := rec.child.CalculateSize(
+func (rec *RectangleComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) (rl.Vector2, error) {
+	if rec.child == nil {
+		rec.size = rl.Vector2{X: rec.padding.HorizontalSum(), Y: rec.padding.VerticalSum()}
+
+		return rec.size, nil
+	}
+
+	childSize, err := rec.child.CalculateSize(
 		getFont,
 		rl.Vector2Add(
 			maxViewport,
 			rl.Vector2{X: -rec.padding.HorizontalSum(), Y: -rec.padding.VerticalSum()},
 		),
 	)
+	if err != nil {
+		return rl.Vector2Zero(), err
+	}
 
 	rec.size = rl.Vector2Add(childSize, rl.Vector2{X: rec.padding.HorizontalSum(), Y: rec.padding.VerticalSum()})
 
 	rec.child.SetPositionOffset(rec.getChildPositionOffset())
 
+	return rec.size, nil
+}
+
+// GetSize returns the size calculated by the last layout.
+func (rec *RectangleComponent) GetSize() rl.Vector2 {
 	return rec.size
 }
 
@@ -102,6 +189,38 @@ func (rec *RectangleComponent) GetEventBus() *atoms.EventBus {
 	return rec.eventBus
 }
 
+func (rec *RectangleComponent) SetRoundness(roundness float32) error {
+	if roundness < 0 {
+		return fmt.Errorf("%w: roundness can't be less than 0", ErrInvalidProperty)
+	}
+
+	rec.roundness = roundness
+
+	return nil
+}
+
+// BindRoundness keeps the roundness equal to the value of the signal, negative values are ignored.
+func (rec *RectangleComponent) BindRoundness(signal atoms.ReadonlySignal[float32]) atoms.UnsubscribeFunc {
+	return bind(signal, func(roundness float32) {
+		if roundness >= 0 {
+			rec.SetRoundness(roundness)
+		}
+	})
+}
+
+func (rec *RectangleComponent) GetBackgroundColor() rl.Color {
+	return rec.backgroundColor
+}
+
+// SetBackgroundColor doesn't change the size of the rectangle, so it's used in the next frame without recalculating the layout.
+func (rec *RectangleComponent) SetBackgroundColor(color rl.Color) {
+	rec.backgroundColor = color
+}
+
+func (rec *RectangleComponent) BindBackgroundColor(signal atoms.ReadonlySignal[rl.Color]) atoms.UnsubscribeFunc {
+	return bind(signal, rec.SetBackgroundColor)
+}
+
 func (rec *RectangleComponent) GetPaddingTop() float32 {
 	return rec.padding.Top()
 }
@@ -120,20 +239,42 @@ func (rec *RectangleComponent) GetPaddingBottom() float32 {
 
 func (rec *RectangleComponent) SetPaddingTop(value float32) {
 	rec.padding.SetTop(value)
-	rec.eventBus.DispatchEvent("gui:schedule-recalculation", nil)
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
 }
 
 func (rec *RectangleComponent) SetPaddingLeft(value float32) {
 	rec.padding.SetLeft(value)
-	rec.eventBus.DispatchEvent("gui:schedule-recalculation", nil)
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
 }
 
 func (rec *RectangleComponent) SetPaddingRight(value float32) {
 	rec.padding.SetRight(value)
-	rec.eventBus.DispatchEvent("gui:schedule-recalculation", nil)
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
 }
 
 func (rec *RectangleComponent) SetPaddingBottom(value float32) {
 	rec.padding.SetBottom(value)
-	rec.eventBus.DispatchEvent("gui:schedule-recalculation", nil)
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+// SetPadding sets the same padding on all sides.
+func (rec *RectangleComponent) SetPadding(value float32) {
+	if rec.padding.Top() == value && rec.padding.Bottom() == value && rec.padding.Left() == value && rec.padding.Right() == value {
+		return
+	}
+
+	rec.padding.SetTop(value)
+	rec.padding.SetBottom(value)
+	rec.padding.SetLeft(value)
+	rec.padding.SetRight(value)
+	atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+func (rec *RectangleComponent) BindPadding(signal atoms.ReadonlySignal[float32]) atoms.UnsubscribeFunc {
+	return bind(signal, rec.SetPadding)
+}
+
+// GetTypeName is matched by type selectors of style sheets.
+func (rec *RectangleComponent) GetTypeName() string {
+	return "Rectangle"
 }
@@ -0,0 +1,148 @@
+package components
+
+import (
+	"strconv"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// styledRectangle and styledText let style sheets reach built-in components embedded into custom ones.
+type styledRectangle interface {
+	styledRectangle() *RectangleComponent
+}
+
+type styledText interface {
+	styledText() *TextComponent
+}
+
+func (rec *RectangleComponent) styledRectangle() *RectangleComponent {
+	return rec
+}
+
+func (comp *TextComponent) styledText() *TextComponent {
+	return comp
+}
+
+type styleProperty struct {
+	parse func(value string) (interface{}, error)
+	// get returns false if the component doesn't have the property.
+	get   func(component Component) (interface{}, bool)
+	apply func(component Component, value interface{})
+}
+
+func rectangleProperty(parse func(value string) (interface{}, error), get func(rec *RectangleComponent) interface{}, apply func(rec *RectangleComponent, value interface{})) styleProperty {
+	return styleProperty{
+		parse: parse,
+		get: func(component Component) (interface{}, bool) {
+			if rectangle, ok := component.(styledRectangle); ok {
+				return get(rectangle.styledRectangle()), true
+			}
+
+			return nil, false
+		},
+		apply: func(component Component, value interface{}) {
+			if rectangle, ok := component.(styledRectangle); ok {
+				apply(rectangle.styledRectangle(), value)
+			}
+		},
+	}
+}
+
+func textProperty(parse func(value string) (interface{}, error), get func(comp *TextComponent) interface{}, apply func(comp *TextComponent, value interface{})) styleProperty {
+	return styleProperty{
+		parse: parse,
+		get: func(component Component) (interface{}, bool) {
+			if text, ok := component.(styledText); ok {
+				return get(text.styledText()), true
+			}
+
+			return nil, false
+		},
+		apply: func(component Component, value interface{}) {
+			if text, ok := component.(styledText); ok {
+				apply(text.styledText(), value)
+			}
+		},
+	}
+}
+
+func parsedAs[T any](parse func(value string) (T, error)) func(value string) (interface{}, error) {
+	return func(value string) (interface{}, error) {
+		return parse(value)
+	}
+}
+
+func parseStyleNumber(min float32) func(value string) (interface{}, error) {
+	return parsedAs(func(value string) (float32, error) {
+		return parseNumber(value, min)
+	})
+}
+
+func parseStyleName(names map[string]int) func(value string) (interface{}, error) {
+	return parsedAs(func(value string) (int, error) {
+		return parseName(names, value)
+	})
+}
+
+// Values are validated when the style sheet is parsed, so setters can't fail when they are applied.
+var styleProperties = map[string]styleProperty{
+	"backgroundColor": rectangleProperty(
+		parsedAs(parseColor),
+		func(rec *RectangleComponent) interface{} { return rec.backgroundColor },
+		func(rec *RectangleComponent, value interface{}) { rec.SetBackgroundColor(value.(rl.Color)) },
+	),
+	"padding": rectangleProperty(
+		parsedAs(parsePadding),
+		func(rec *RectangleComponent) interface{} { return rec.padding },
+		func(rec *RectangleComponent, value interface{}) {
+			rec.padding = value.(atoms.ClockValues)
+			atoms.Publish(rec.eventBus, ScheduleRecalculationEvent, struct{}{})
+		},
+	),
+	"roundness": rectangleProperty(
+		parseStyleNumber(0),
+		func(rec *RectangleComponent) interface{} { return rec.roundness },
+		func(rec *RectangleComponent, value interface{}) { rec.SetRoundness(value.(float32)) },
+	),
+	"color": textProperty(
+		parsedAs(parseColor),
+		func(comp *TextComponent) interface{} { return comp.color },
+		func(comp *TextComponent, value interface{}) { comp.SetColor(value.(rl.Color)) },
+	),
+	"font": textProperty(
+		parsedAs(func(value string) (string, error) { return value, nil }),
+		func(comp *TextComponent) interface{} { return comp.fontName },
+		func(comp *TextComponent, value interface{}) { comp.SetFontName(value.(string)) },
+	),
+	"fontSize": textProperty(
+		parseStyleNumber(1),
+		func(comp *TextComponent) interface{} { return comp.fontSize },
+		func(comp *TextComponent, value interface{}) { comp.SetFontSize(value.(float32)) },
+	),
+	"spacing": textProperty(
+		parseStyleNumber(0),
+		func(comp *TextComponent) interface{} { return comp.spacing },
+		func(comp *TextComponent, value interface{}) { comp.SetSpacing(value.(float32)) },
+	),
+	"fontWeight": textProperty(
+		parsedAs(parseFontWeight),
+		func(comp *TextComponent) interface{} { return comp.fontWeight },
+		func(comp *TextComponent, value interface{}) { comp.SetFontWeight(value.(int)) },
+	),
+	"fontStyle": textProperty(
+		parseStyleName(fontStyleNames),
+		func(comp *TextComponent) interface{} { return comp.fontStyle },
+		func(comp *TextComponent, value interface{}) { comp.SetFontStyle(value.(int)) },
+	),
+	"textAlign": textProperty(
+		parseStyleName(textAlignNames),
+		func(comp *TextComponent) interface{} { return comp.textAlign },
+		func(comp *TextComponent, value interface{}) { comp.SetTextAlign(value.(int)) },
+	),
+	"wrap": textProperty(
+		parsedAs(strconv.ParseBool),
+		func(comp *TextComponent) interface{} { return comp.wrapText },
+		func(comp *TextComponent, value interface{}) { comp.SetWrapText(value.(bool)) },
+	),
+}
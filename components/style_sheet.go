@@ -0,0 +1,461 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Style sheets set properties of components centrally, with CSS-like rules:
+//
+//	/* Every text, and titles with a bigger font. */
+//	Text { font: Roboto; color: #202020 }
+//	Text.title, #header Text { fontSize: 32; fontWeight: 700 }
+//	Rectangle.primary { backgroundColor: blue; padding: 8 16; roundness: 0.2 }
+//	Rectangle.primary:hover { backgroundColor: darkblue }
+//
+// Selectors match type names (see GetTypeName), IDs, tags as classes and states (see ComponentNode.SetState),
+// and can be combined with spaces to match descendants. Property names and values are the same as markup attributes.
+// When more rules set the same property the most specific one wins: IDs, then classes and states, then types.
+// Rules with the same specificity are applied in order, so the last one wins.
+
+const StateHover = "hover"
+const StatePressed = "pressed"
+const StateFocused = "focused"
+const StateDisabled = "disabled"
+
+// TypedComponent is implemented by components which can be targeted by type selectors.
+// Custom components embedding a built-in one can return their own name, e.g. Button.
+type TypedComponent interface {
+	GetTypeName() string
+}
+
+type compoundSelector struct {
+	typeName string
+	id       string
+	classes  []string
+	states   []string
+}
+
+type styleDeclaration struct {
+	property string
+	value    interface{}
+}
+
+type styleRule struct {
+	// selector is a list of compound selectors, each of them matching a descendant of the previous one.
+	selector     []compoundSelector
+	specificity  [3]int
+	declarations []styleDeclaration
+}
+
+type StyleSheet struct {
+	rules []*styleRule
+
+	// matching is reused to collect the rules matching a component, so unchanged components don't allocate.
+	matching []*styleRule
+}
+
+// nodeStyle is what a style sheet applied to a component last time.
+type nodeStyle struct {
+	sheet *StyleSheet
+	rules []*styleRule
+	// styled are the values set by the sheet, unstyled the values from before it set them.
+	styled   map[string]interface{}
+	unstyled map[string]interface{}
+}
+
+func newNodeStyle() *nodeStyle {
+	return &nodeStyle{
+		sheet:    nil,
+		rules:    []*styleRule{},
+		styled:   map[string]interface{}{},
+		unstyled: map[string]interface{}{},
+	}
+}
+
+func LoadStyleSheetFile(path string) (*StyleSheet, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := ParseStyleSheet(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return sheet, nil
+}
+
+// ParseStyleSheet returns a *MarkupError pointing to the invalid selector or declaration.
+func ParseStyleSheet(source string) (*StyleSheet, error) {
+	stripped := stripComments(source)
+	parser := &styleSheetParser{source: stripped, offset: 0, positions: newMarkupPositions(stripped)}
+	sheet := &StyleSheet{rules: []*styleRule{}, matching: []*styleRule{}}
+
+	for {
+		parser.skipWhitespace()
+
+		if parser.offset >= len(parser.source) {
+			break
+		}
+
+		selectorsStart := parser.offset
+		selectorsText, ok := parser.readUntil('{')
+
+		if !ok {
+			return nil, parser.positionAt(selectorsStart).errorf("expected { after the selector")
+		}
+
+		selectors, err := parser.parseSelectors(selectorsText, selectorsStart)
+		if err != nil {
+			return nil, err
+		}
+
+		declarationsStart := parser.offset
+		declarationsText, ok := parser.readUntil('}')
+
+		if !ok {
+			return nil, parser.positionAt(declarationsStart - 1).errorf("rule isn't closed with }")
+		}
+
+		declarations, err := parser.parseDeclarations(declarationsText, declarationsStart)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, selector := range selectors {
+			sheet.rules = append(sheet.rules, &styleRule{
+				selector:     selector,
+				specificity:  calculateSpecificity(selector),
+				declarations: declarations,
+			})
+		}
+	}
+
+	// The sort is stable, so rules of the same specificity stay in the source order.
+	slices.SortStableFunc(sheet.rules, func(a *styleRule, b *styleRule) int {
+		return slices.Compare(a.specificity[:], b.specificity[:])
+	})
+
+	return sheet, nil
+}
+
+// Apply sets the styled properties of the tree. Properties which stop being styled, e.g. when a state is turned off,
+// get back their values from before. The app applies its style sheet every frame, before the layout.
+// Components are restyled only when the rules matching them change, so values set in the meantime by bindings
+// or the app itself stay, and are the ones restored when the rules stop matching.
+func (sheet *StyleSheet) Apply(root Component) {
+	Walk(root, func(component Component) bool {
+		if nodeComponent, ok := component.(NodeComponent); ok {
+			sheet.applyTo(nodeComponent)
+		}
+
+		return true
+	})
+}
+
+func (sheet *StyleSheet) applyTo(component NodeComponent) {
+	style := component.getStyle()
+
+	// Rules are sorted by specificity, so the more specific ones overwrite the others.
+	sheet.matching = sheet.matching[:0]
+
+	for _, rule := range sheet.rules {
+		if rule.matches(component) {
+			sheet.matching = append(sheet.matching, rule)
+		}
+	}
+
+	if style.sheet == sheet && slices.Equal(style.rules, sheet.matching) {
+		return
+	}
+
+	// Styled properties changed by a setter since the last time keep the new value as the one from before the sheet.
+	for property, value := range style.styled {
+		if current, _ := styleProperties[property].get(component); current != value {
+			style.unstyled[property] = current
+		}
+	}
+
+	styled := map[string]interface{}{}
+
+	for _, rule := range sheet.matching {
+		for _, declaration := range rule.declarations {
+			styled[declaration.property] = declaration.value
+		}
+	}
+
+	for property, value := range style.unstyled {
+		if _, ok := styled[property]; !ok {
+			styleProperties[property].apply(component, value)
+			delete(style.unstyled, property)
+		}
+	}
+
+	for property, value := range styled {
+		current, supported := styleProperties[property].get(component)
+
+		if !supported {
+			delete(styled, property)
+			continue
+		}
+
+		if _, ok := style.unstyled[property]; !ok {
+			style.unstyled[property] = current
+		}
+
+		if current != value {
+			styleProperties[property].apply(component, value)
+		}
+	}
+
+	style.sheet = sheet
+	style.rules = slices.Clone(sheet.matching)
+	style.styled = styled
+}
+
+func (rule *styleRule) matches(component NodeComponent) bool {
+	last := len(rule.selector) - 1
+
+	if !rule.selector[last].matches(component) {
+		return false
+	}
+
+	// The remaining compound selectors have to match ancestors, from the nearest one.
+	next := last - 1
+
+	for _, ancestor := range Ancestors(component) {
+		if next < 0 {
+			break
+		}
+
+		if nodeAncestor, ok := ancestor.(NodeComponent); ok && rule.selector[next].matches(nodeAncestor) {
+			next--
+		}
+	}
+
+	return next < 0
+}
+
+func (selector compoundSelector) matches(component NodeComponent) bool {
+	if selector.typeName != "" {
+		typed, ok := component.(TypedComponent)
+
+		if !ok || typed.GetTypeName() != selector.typeName {
+			return false
+		}
+	}
+
+	if selector.id != "" && component.GetID() != selector.id {
+		return false
+	}
+
+	for _, class := range selector.classes {
+		if !component.HasTag(class) {
+			return false
+		}
+	}
+
+	for _, state := range selector.states {
+		if !component.HasState(state) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func calculateSpecificity(selector []compoundSelector) [3]int {
+	specificity := [3]int{}
+
+	for _, compound := range selector {
+		if compound.id != "" {
+			specificity[0]++
+		}
+
+		specificity[1] += len(compound.classes) + len(compound.states)
+
+		if compound.typeName != "" {
+			specificity[2]++
+		}
+	}
+
+	return specificity
+}
+
+type styleSheetParser struct {
+	source    string
+	offset    int
+	positions *markupPositions
+}
+
+// stripComments replaces comments with spaces, so offsets (and lines) of the rest of the source stay the same.
+func stripComments(source string) string {
+	stripped := []byte(source)
+	offset := 0
+
+	for {
+		start := strings.Index(source[offset:], "/*")
+		if start == -1 {
+			return string(stripped)
+		}
+
+		start += offset
+		end := len(source)
+
+		if length := strings.Index(source[start+2:], "*/"); length != -1 {
+			end = start + 2 + length + 2
+		}
+
+		for i := start; i < end; i++ {
+			if stripped[i] != '\n' {
+				stripped[i] = ' '
+			}
+		}
+
+		offset = end
+	}
+}
+
+func (parser *styleSheetParser) positionAt(offset int) markupPosition {
+	return parser.positions.at(offset)
+}
+
+func (parser *styleSheetParser) skipWhitespace() {
+	for parser.offset < len(parser.source) && strings.ContainsRune(" \t\r\n", rune(parser.source[parser.offset])) {
+		parser.offset++
+	}
+}
+
+// readUntil returns the text up to the delimiter and moves after it.
+func (parser *styleSheetParser) readUntil(delimiter byte) (string, bool) {
+	end := strings.IndexByte(parser.source[parser.offset:], delimiter)
+	if end == -1 {
+		return "", false
+	}
+
+	text := parser.source[parser.offset : parser.offset+end]
+	parser.offset += end + 1
+
+	return text, true
+}
+
+// trimmedOffset returns the offset of the first non-whitespace character of the part, which starts at offset.
+func trimmedOffset(part string, offset int) int {
+	return offset + len(part) - len(strings.TrimLeft(part, " \t\r\n"))
+}
+
+func (parser *styleSheetParser) parseSelectors(text string, offset int) ([][]compoundSelector, error) {
+	selectors := [][]compoundSelector{}
+
+	for _, part := range strings.Split(text, ",") {
+		position := parser.positionAt(trimmedOffset(part, offset))
+		offset += len(part) + 1
+
+		compounds := []compoundSelector{}
+
+		for _, compoundText := range strings.Fields(part) {
+			compound, err := parseCompoundSelector(compoundText)
+			if err != nil {
+				return nil, position.errorf("invalid selector %q: %v", strings.TrimSpace(part), err)
+			}
+
+			compounds = append(compounds, compound)
+		}
+
+		if len(compounds) == 0 {
+			return nil, position.errorf("empty selector")
+		}
+
+		selectors = append(selectors, compounds)
+	}
+
+	return selectors, nil
+}
+
+func parseCompoundSelector(text string) (compoundSelector, error) {
+	selector := compoundSelector{classes: []string{}, states: []string{}}
+
+	// Every part starts with its prefix, except the type name, which can be only the first one.
+	kind := byte(0)
+	start := 0
+
+	flush := func(end int) error {
+		name := text[start:end]
+
+		if name == "" && !(kind == 0 && end == 0) {
+			return fmt.Errorf("missing name")
+		}
+
+		switch kind {
+		case 0:
+			if name != "*" {
+				selector.typeName = name
+			}
+		case '#':
+			if selector.id != "" {
+				return fmt.Errorf("more than one ID")
+			}
+
+			selector.id = name
+		case '.':
+			selector.classes = append(selector.classes, name)
+		case ':':
+			selector.states = append(selector.states, name)
+		}
+
+		return nil
+	}
+
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte("#.:", text[i]) == -1 {
+			continue
+		}
+
+		if err := flush(i); err != nil {
+			return selector, err
+		}
+
+		kind = text[i]
+		start = i + 1
+	}
+
+	return selector, flush(len(text))
+}
+
+func (parser *styleSheetParser) parseDeclarations(text string, offset int) ([]styleDeclaration, error) {
+	declarations := []styleDeclaration{}
+
+	for _, part := range strings.Split(text, ";") {
+		position := parser.positionAt(trimmedOffset(part, offset))
+		offset += len(part) + 1
+
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, position.errorf("expected property: value, got %q", strings.TrimSpace(part))
+		}
+
+		name = strings.TrimSpace(name)
+
+		property, ok := styleProperties[name]
+		if !ok {
+			return nil, position.errorf("unknown property %q", name)
+		}
+
+		parsed, err := property.parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, position.errorf("invalid value of %s: %v", name, err)
+		}
+
+		declarations = append(declarations, styleDeclaration{name, parsed})
+	}
+
+	return declarations, nil
+}
@@ -0,0 +1,191 @@
+package components
+
+import (
+	"errors"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// button is a custom component, which is styled like a rectangle but targeted by its own type name.
+type button struct {
+	RectangleComponent
+}
+
+func (b *button) GetTypeName() string {
+	return "Button"
+}
+
+const testStyleSheet = `
+/* Defaults */
+Text { color: black; fontSize: 14 }
+Rectangle { backgroundColor: white }
+
+Button { backgroundColor: gray; padding: 4 8 }
+Button.primary { backgroundColor: blue }
+Button.primary:hover { backgroundColor: darkblue }
+
+#sidebar Text { color: gray }
+Text.title { fontSize: 32; fontWeight: 700 }
+Text { fontStyle: italic }
+`
+
+func TestStyleSheets(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	sheet, err := ParseStyleSheet(testStyleSheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	title := NewTextComponent(eventBus, "Title", "Roboto", 16, 0, rl.Red)
+	title.AddTag("title")
+
+	label := NewTextComponent(eventBus, "Label", "Roboto", 16, 0, rl.Red)
+
+	rectangle, _ := NewRectangleComponent(eventBus, nil, rl.Blank, 0)
+	primary := &button{RectangleComponent: *rectangle}
+	primary.SetChild(label)
+	primary.AddTag("primary")
+
+	sidebar, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	sidebar.SetID("sidebar")
+	sidebar.AddChild(primary)
+
+	root, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	root.AddChild(title)
+	root.AddChild(sidebar)
+
+	sheet.Apply(root)
+
+	t.Run("More specific rules win", func(t *testing.T) {
+		if title.GetColor() != rl.Black || title.fontSize != 32 || title.fontWeight != FontWeightBold || title.fontStyle != FontStyleItalic {
+			t.Errorf("Unexpected title style: %v %f %d %d", title.GetColor(), title.fontSize, title.fontWeight, title.fontStyle)
+		}
+
+		if label.GetColor() != rl.Gray || label.fontSize != 14 {
+			t.Errorf("Expected the descendant rule to color the label gray, got %v %f", label.GetColor(), label.fontSize)
+		}
+
+		if primary.GetBackgroundColor() != rl.Blue || primary.GetPaddingLeft() != 8 || primary.GetPaddingTop() != 4 {
+			t.Errorf("Unexpected button style: %v %f", primary.GetBackgroundColor(), primary.GetPaddingLeft())
+		}
+	})
+
+	t.Run("States restyle components and restore them", func(t *testing.T) {
+		primary.SetState(StateHover, true)
+		sheet.Apply(root)
+
+		if primary.GetBackgroundColor() != rl.DarkBlue {
+			t.Errorf("Expected the hover color, got %v", primary.GetBackgroundColor())
+		}
+
+		primary.SetState(StateHover, false)
+		primary.RemoveTag("primary")
+		sheet.Apply(root)
+
+		if primary.GetBackgroundColor() != rl.Gray {
+			t.Errorf("Expected the plain button color, got %v", primary.GetBackgroundColor())
+		}
+
+		title.RemoveTag("title")
+		sheet.Apply(root)
+
+		if title.fontSize != 14 || title.fontWeight != FontWeightNormal {
+			t.Errorf("Expected the title to lose its style, got %f %d", title.fontSize, title.fontWeight)
+		}
+
+		if err := root.RemoveChild(title); err != nil {
+			t.Fatal(err)
+		}
+
+		// Without any rule the values from before the style sheet come back.
+		mustParseStyleSheet(t, "Layout { }").Apply(title)
+
+		if title.GetColor() != rl.Red || title.fontSize != 16 {
+			t.Errorf("Expected the unstyled title, got %v %f", title.GetColor(), title.fontSize)
+		}
+	})
+
+	t.Run("Unchanged styles don't schedule a relayout", func(t *testing.T) {
+		recalculations := 0
+		atoms.Subscribe(eventBus, ScheduleRecalculationEvent, func(struct{}) {
+			recalculations++
+		})
+
+		sheet.Apply(root)
+		sheet.Apply(root)
+
+		if recalculations != 0 {
+			t.Errorf("Expected no relayout, got %d", recalculations)
+		}
+	})
+
+	t.Run("Values set outside the style sheet stay", func(t *testing.T) {
+		text := NewTextComponent(eventBus, "Bound", "Roboto", 16, 0, rl.Red)
+		text.AddTag("accent")
+
+		color := atoms.NewSignal(rl.Red)
+		text.BindColor(color)
+
+		accentSheet := mustParseStyleSheet(t, "Text.accent { color: blue }")
+		accentSheet.Apply(text)
+
+		if text.GetColor() != rl.Blue {
+			t.Fatalf("Expected the styled color, got %v", text.GetColor())
+		}
+
+		color.Set(rl.Green)
+		accentSheet.Apply(text)
+
+		if text.GetColor() != rl.Green {
+			t.Errorf("Expected the bound color not to be overwritten, got %v", text.GetColor())
+		}
+
+		text.RemoveTag("accent")
+		accentSheet.Apply(text)
+
+		if text.GetColor() != rl.Green {
+			t.Errorf("Expected the bound color to be restored, got %v", text.GetColor())
+		}
+	})
+
+	errorCases := []struct {
+		source string
+		line   int
+		column int
+	}{
+		{"Text {\n\tcolor: black;\n\tfontSize: -1\n}", 3, 2},
+		{"Text { colour: black }", 1, 8},
+		{"Text,\n  Rectangle#a#b { color: black }", 2, 3},
+		{"Text { color: black", 1, 6},
+	}
+
+	for _, errorCase := range errorCases {
+		t.Run("Errors have positions", func(t *testing.T) {
+			_, err := ParseStyleSheet(errorCase.source)
+
+			var markupError *MarkupError
+
+			if !errors.As(err, &markupError) || !errors.Is(err, ErrInvalidMarkup) {
+				t.Fatalf("Expected a markup error, got %v", err)
+			}
+
+			if markupError.Line != errorCase.line || markupError.Column != errorCase.column {
+				t.Errorf("Expected the error at %d:%d, got %v", errorCase.line, errorCase.column, err)
+			}
+		})
+	}
+}
+
+func mustParseStyleSheet(t *testing.T, source string) *StyleSheet {
+	t.Helper()
+
+	sheet, err := ParseStyleSheet(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sheet
+}
@@ -8,32 +8,96 @@ import (
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+// DefaultLineSpacing mirrors the default value of raylib's global text line spacing, for more context see:
+// https://github.com/raysan5/raylib/blob/9e39788e077f1d35c5fe54600f2143423a80bb3d/src/rtext.c#L1164
+const DefaultLineSpacing = 2
+
+const TextAlignLeft = 0
+const TextAlignCenter = 1
+const TextAlignRight = 2
+const TextAlignJustify = 3
+const TextAlignStart = 4
+const TextAlignEnd = 5
+
+const FontWeightThin = 100
+const FontWeightExtraLight = 200
+const FontWeightLight = 300
+const FontWeightNormal = 400
+const FontWeightMedium = 500
+const FontWeightSemiBold = 600
+const FontWeightBold = 700
+const FontWeightExtraBold = 800
+const FontWeightBlack = 900
+
+const FontStyleNormal = 0
+const FontStyleItalic = 1
+
+const TextDirectionAuto = 0
+const TextDirectionLTR = 1
+const TextDirectionRTL = 2
+
 type TextComponent struct {
 	Component
+	ComponentNode
 
 	text          string
 	processedText string
 	wrapText      bool
+	maxLines      int
+	overflow      int
+	ellipsis      string
+	truncated     bool
+	lines         []textLine
+	size          rl.Vector2
+	textAlign     int
+	textDirection int
 	fontName      string
+	fontWeight    int
+	fontStyle     int
 	fontSize      float32
 	spacing       float32
 	color         rl.Color
 	position      ComponentPosition
 
+	lineHeight           float32
+	lineHeightMultiplier float32
+	paragraphSpacing     float32
+
+	effects atoms.TextEffects
+
 	eventBus *atoms.EventBus
 }
 
 func NewTextComponent(eventBus *atoms.EventBus, text string, loadedFontName string, fontSize float32, spacing float32, color rl.Color) *TextComponent {
 	return &TextComponent{
+		ComponentNode: NewComponentNode(),
+
 		text:          text,
 		processedText: "",
 		wrapText:      false,
+		maxLines:      0,
+		overflow:      OverflowVisible,
+		ellipsis:      DefaultEllipsis,
+		truncated:     false,
+		lines:         nil,
+		size:          rl.Vector2Zero(),
+		textAlign:     TextAlignStart,
+		textDirection: TextDirectionAuto,
 		fontName:      loadedFontName,
+		fontWeight:    FontWeightNormal,
+		fontStyle:     FontStyleNormal,
 		fontSize:      fontSize,
 		spacing:       spacing,
 		color:         color,
 		position:      NewComponentPosition(),
-		eventBus:      eventBus,
+
+		lineHeight:           0,
+		lineHeightMultiplier: 0,
+		paragraphSpacing:     0,
+
+		effects: atoms.TextEffects{},
+
+		eventBus: eventBus,
 	}
 }
 
@@ -41,134 +105,611 @@ func (comp *TextComponent) SetWrapText(wrap bool) {
 	comp.wrapText = wrap
 }
 
-func wrapText(font atoms.Font, text string, maxWidth float32) (processedText string, calculatedSize rl.Vector2) {
-	type IterationState struct {
-		wrappedText      strings.Builder
-		currentLineText  strings.Builder
-		currentLineWidth float32
+func (comp *TextComponent) SetTextAlign(textAlign int) error {
+	if textAlign != TextAlignLeft && textAlign != TextAlignCenter && textAlign != TextAlignRight && textAlign != TextAlignJustify && textAlign != TextAlignStart && textAlign != TextAlignEnd {
+		return fmt.Errorf("%w: unknown value for textAlign property: %d", ErrInvalidProperty, textAlign)
 	}
 
-	processEndOfInput := func(state *IterationState) {
-		state.wrappedText.WriteString(state.currentLineText.String())
-		calculatedSize.Y += font.LineHeight()
+	comp.textAlign = textAlign
 
-		if state.currentLineWidth > calculatedSize.X {
-			calculatedSize.X = state.currentLineWidth
-		}
+	return nil
+}
+
+// SetFontWeight selects a variant of the font family by its weight, from 100 (thin) to 900 (black).
+// If the family has no variant of this weight, the nearest one is used.
+func (comp *TextComponent) SetFontWeight(fontWeight int) error {
+	if fontWeight < FontWeightThin || fontWeight > FontWeightBlack || fontWeight%100 != 0 {
+		return fmt.Errorf("%w: unknown value for fontWeight property: %d", ErrInvalidProperty, fontWeight)
+	}
+
+	comp.fontWeight = fontWeight
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (comp *TextComponent) SetFontStyle(fontStyle int) error {
+	if fontStyle != FontStyleNormal && fontStyle != FontStyleItalic {
+		return fmt.Errorf("%w: unknown value for fontStyle property: %d", ErrInvalidProperty, fontStyle)
+	}
+
+	comp.fontStyle = fontStyle
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+// SetTextDirection sets the base direction of paragraphs, by default it is detected from the first strongly directional character.
+func (comp *TextComponent) SetTextDirection(textDirection int) error {
+	if textDirection != TextDirectionAuto && textDirection != TextDirectionLTR && textDirection != TextDirectionRTL {
+		return fmt.Errorf("%w: unknown value for textDirection property: %d", ErrInvalidProperty, textDirection)
+	}
+
+	comp.textDirection = textDirection
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+// SetLineHeight sets the distance between the tops of two consecutive lines in pixels.
+func (comp *TextComponent) SetLineHeight(lineHeight float32) error {
+	if lineHeight < 0 {
+		return fmt.Errorf("%w: line height can't be less than 0", ErrInvalidProperty)
+	}
+
+	comp.lineHeight = lineHeight
+	comp.lineHeightMultiplier = 0
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+// SetLineHeightMultiplier sets the line height relative to the font size, e.g. 1.5 for one and a half line spacing.
+func (comp *TextComponent) SetLineHeightMultiplier(multiplier float32) error {
+	if multiplier < 0 {
+		return fmt.Errorf("%w: line height multiplier can't be less than 0", ErrInvalidProperty)
+	}
+
+	comp.lineHeight = 0
+	comp.lineHeightMultiplier = multiplier
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (comp *TextComponent) SetParagraphSpacing(paragraphSpacing float32) error {
+	if paragraphSpacing < 0 {
+		return fmt.Errorf("%w: paragraph spacing can't be less than 0", ErrInvalidProperty)
 	}
 
-	processEndOfLine := func(state *IterationState) {
-		if state.currentLineWidth > calculatedSize.X {
-			calculatedSize.X = state.currentLineWidth
+	comp.paragraphSpacing = paragraphSpacing
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+// SetOutline draws an outline of the given width in pixels around glyphs. It works only with fonts loaded as SDF.
+// The distance field reaches about a fifth of the font size outside of glyphs, wider outlines are narrowed to it.
+func (comp *TextComponent) SetOutline(width float32, color rl.Color) error {
+	if width < 0 {
+		return fmt.Errorf("%w: outline width can't be less than 0", ErrInvalidProperty)
+	}
+
+	comp.effects.OutlineWidth = width
+	comp.effects.OutlineColor = color
+
+	return nil
+}
+
+// SetGlow draws a glow fading out over the given radius in pixels around glyphs (and their outline). It works only with fonts loaded as SDF.
+// The glow ends where the distance field does, about a fifth of the font size outside of glyphs.
+func (comp *TextComponent) SetGlow(radius float32, color rl.Color) error {
+	if radius < 0 {
+		return fmt.Errorf("%w: glow radius can't be less than 0", ErrInvalidProperty)
+	}
+
+	comp.effects.GlowRadius = radius
+	comp.effects.GlowColor = color
+
+	return nil
+}
+
+func (comp *TextComponent) calculateLineHeight() float32 {
+	if comp.lineHeight != 0 {
+		return comp.lineHeight
+	}
+
+	if comp.lineHeightMultiplier != 0 {
+		return comp.fontSize * comp.lineHeightMultiplier
+	}
+
+	return comp.fontSize + DefaultLineSpacing
+}
+
+// SetMaxLines limits the number of rendered lines, 0 means no limit.
+func (comp *TextComponent) SetMaxLines(maxLines int) error {
+	if maxLines < 0 {
+		return fmt.Errorf("%w: max lines can't be less than 0", ErrInvalidProperty)
+	}
+
+	comp.maxLines = maxLines
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (comp *TextComponent) SetOverflow(overflow int) error {
+	if overflow != OverflowVisible && overflow != OverflowClip && overflow != OverflowEllipsis && overflow != OverflowEllipsisMiddle {
+		return fmt.Errorf("%w: unknown value for overflow property: %d", ErrInvalidProperty, overflow)
+	}
+
+	comp.overflow = overflow
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (comp *TextComponent) SetEllipsis(ellipsis string) {
+	comp.ellipsis = ellipsis
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+// GetText returns the full text, even if only a part of it fits on the screen (useful for tooltips).
+func (comp *TextComponent) GetText() string {
+	return comp.text
+}
+
+func (comp *TextComponent) SetText(text string) {
+	if comp.text == text {
+		return
+	}
+
+	comp.text = text
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+// BindText keeps the text equal to the value of the signal.
+func (comp *TextComponent) BindText(signal atoms.ReadonlySignal[string]) atoms.UnsubscribeFunc {
+	return bind(signal, comp.SetText)
+}
+
+// SetFontName changes the font family, it has to be added to the app like the one passed to NewTextComponent.
+func (comp *TextComponent) SetFontName(fontName string) {
+	if comp.fontName == fontName {
+		return
+	}
+
+	comp.fontName = fontName
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+func (comp *TextComponent) SetFontSize(fontSize float32) error {
+	if fontSize <= 0 {
+		return fmt.Errorf("%w: fontSize has to be greater than 0, got %f", ErrInvalidProperty, fontSize)
+	}
+
+	if comp.fontSize == fontSize {
+		return nil
+	}
+
+	comp.fontSize = fontSize
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+func (comp *TextComponent) BindFontName(signal atoms.ReadonlySignal[string]) atoms.UnsubscribeFunc {
+	return bind(signal, comp.SetFontName)
+}
+
+// BindFontSize keeps the font size equal to the value of the signal, values not greater than 0 are ignored.
+func (comp *TextComponent) BindFontSize(signal atoms.ReadonlySignal[float32]) atoms.UnsubscribeFunc {
+	return bind(signal, func(fontSize float32) {
+		if fontSize > 0 {
+			comp.SetFontSize(fontSize)
 		}
+	})
+}
+
+func (comp *TextComponent) SetSpacing(spacing float32) {
+	comp.spacing = spacing
+	atoms.Publish(comp.eventBus, ScheduleRecalculationEvent, struct{}{})
+}
+
+func (comp *TextComponent) GetColor() rl.Color {
+	return comp.color
+}
+
+// SetColor doesn't change the size of the text, so it's used in the next frame without recalculating the layout.
+func (comp *TextComponent) SetColor(color rl.Color) {
+	comp.color = color
+}
+
+func (comp *TextComponent) BindColor(signal atoms.ReadonlySignal[rl.Color]) atoms.UnsubscribeFunc {
+	return bind(signal, comp.SetColor)
+}
+
+// GetProcessedText returns the text exactly as it is rendered, after wrapping and truncation.
+func (comp *TextComponent) GetProcessedText() string {
+	return comp.processedText
+}
+
+// IsTruncated reports whether the last size calculation had to cut off a part of the text.
+func (comp *TextComponent) IsTruncated() bool {
+	return comp.truncated
+}
+
+type textLine struct {
+	text          string
+	width         float32
+	endsParagraph bool
+	rightToLeft   bool
+}
+
+func newTextLine(font atoms.Font, text string, endsParagraph bool) textLine {
+	return textLine{
+		text:          text,
+		width:         measureText(font, text),
+		endsParagraph: endsParagraph,
+		rightToLeft:   false,
+	}
+}
+
+func splitIntoTextLines(font atoms.Font, text string) []textLine {
+	paragraphs := strings.Split(text, "\n")
+	lines := make([]textLine, len(paragraphs))
 
-		state.wrappedText.WriteString(strings.Trim(state.currentLineText.String(), " "))
-		state.currentLineText.Reset()
+	for i, paragraph := range paragraphs {
+		lines[i] = newTextLine(font, normalizeLineText([]rune(paragraph), true), true)
+	}
 
-		state.currentLineWidth = 0
-		state.wrappedText.WriteRune('\n')
+	return lines
+}
 
-		calculatedSize.Y += font.LineHeight()
+func joinTextLines(lines []textLine) string {
+	texts := make([]string, len(lines))
 
-		// This is the line spacing, for more context see original raylib function:
-		// https://github.com/raysan5/raylib/blob/9e39788e077f1d35c5fe54600f2143423a80bb3d/src/rtext.c#L1164
-		// Be aware that text line spacing is a global variable in raylib
-		calculatedSize.Y += 2
+	for i, line := range lines {
+		texts[i] = line.text
 	}
 
-	lastWhitespaceCharacterIndex := -1
-	lastWhitespaceCharacterState := IterationState{}
-	previousCharacterState := IterationState{}
-	state := IterationState{}
+	return strings.Join(texts, "\n")
+}
 
+// wrapText breaks text into lines no wider than maxWidth, at the break opportunities found by findLineBreaks.
+// Words without any break opportunity which don't fit into a line are broken between any two characters,
+// and a glyph wider than maxWidth is put on a line on its own.
+func wrapText(font atoms.Font, text string, maxWidth float32) (lines []textLine) {
 	runes := []rune(text)
+	breaks := findLineBreaks(runes)
 
-	for characterIndex := 0; characterIndex < len(runes); characterIndex++ {
-		character := runes[characterIndex]
+	lineStart := 0
 
-		previousCharacterState = state
+	for {
+		lineEnd := len(runes)
+		nextLineStart := len(runes)
+		endsParagraph := true
 
-		if character == '\n' {
-			processEndOfLine(&state)
-			continue
-		}
+		lastBreak := -1
+
+		var lineWidth float32
+
+		for i := lineStart; i < len(runes); i++ {
+			character := runes[i]
 
-		glyphWidth := font.GlyphWidth(character)
+			if isMandatoryBreakCharacter(character) {
+				lineEnd = i
+				nextLineStart = i + 1
 
-		if state.currentLineWidth+glyphWidth > maxWidth {
-			if state.currentLineText.Len() == 1 {
-				panic("Length of state.currentLineText is equal to 1, unhandled variant.")
+				if character == '\r' && nextLineStart < len(runes) && runes[nextLineStart] == '\n' {
+					nextLineStart++
+				}
+
+				break
 			}
 
-			if lastWhitespaceCharacterIndex != -1 {
-				state = lastWhitespaceCharacterState
-				characterIndex = lastWhitespaceCharacterIndex
-			} else {
-				state = previousCharacterState
-				characterIndex -= 1
+			// Breaking after a soft hyphen makes it visible, so the line has to fit together with the hyphen.
+			if i > lineStart && breaks[i] == breakAllowed && (runes[i-1] != softHyphen || lineWidth+font.Spacing()+font.GlyphWidth('-') <= maxWidth) {
+				lastBreak = i
 			}
 
-			lastWhitespaceCharacterIndex = -1
-			processEndOfLine(&state)
+			characterWidth := getCharacterWidth(font, character)
 
-			continue
+			// Every visible glyph after the first one is drawn after the spacing.
+			if characterWidth > 0 && lineWidth > 0 {
+				characterWidth += font.Spacing()
+			}
+
+			if lineWidth+characterWidth > maxWidth && i > lineStart {
+				if lastBreak != -1 {
+					lineEnd = lastBreak
+				} else {
+					lineEnd = i
+
+					// Combining marks stay with the character they belong to, and a soft hyphen moves to the next line,
+					// as there is no room for the visible hyphen.
+					for lineEnd > lineStart+1 && (getLineBreakClass(runes[lineEnd]) == lineBreakClassCM || runes[lineEnd-1] == softHyphen) {
+						lineEnd--
+					}
+				}
+
+				nextLineStart = lineEnd
+				endsParagraph = false
+
+				break
+			}
+
+			lineWidth += characterWidth
 		}
 
-		if character == ' ' && state.currentLineText.Len() == 0 {
-			continue
+		lines = append(lines, newTextLine(font, normalizeLineText(runes[lineStart:lineEnd], endsParagraph), endsParagraph))
+
+		if lineEnd == len(runes) {
+			break
 		}
 
-		state.currentLineText.WriteRune(character)
-		state.currentLineWidth += glyphWidth
+		lineStart = nextLineStart
 
-		if character == ' ' {
-			lastWhitespaceCharacterIndex = characterIndex
-			lastWhitespaceCharacterState = state
+		if !endsParagraph {
+			for lineStart < len(runes) && runes[lineStart] == ' ' {
+				lineStart++
+			}
 		}
 	}
 
-	processEndOfInput(&state)
+	return
+}
 
-	processedText = state.wrappedText.String()
+// calculateLinesSize measures lines placed lineHeight apart, with additional paragraphSpacing after every paragraph.
+// The last line takes only the height of its glyphs, the same way as in raylib's MeasureTextEx.
+func calculateLinesSize(font atoms.Font, lines []textLine, lineHeight float32, paragraphSpacing float32) (calculatedSize rl.Vector2) {
+	for i, line := range lines {
+		if line.width > calculatedSize.X {
+			calculatedSize.X = line.width
+		}
+
+		if i == len(lines)-1 {
+			calculatedSize.Y += font.LineHeight()
+		} else {
+			calculatedSize.Y += calculateLineAdvance(line, lineHeight, paragraphSpacing)
+		}
+	}
 
 	return
 }
 
-func (comp *TextComponent) SetPosition(pos rl.Vector2) {
-	comp.position.Position = pos
+func calculateLineAdvance(line textLine, lineHeight float32, paragraphSpacing float32) float32 {
+	if line.endsParagraph {
+		return lineHeight + paragraphSpacing
+	}
+
+	return lineHeight
 }
 
-func (comp *TextComponent) SetPositionOffset(offset rl.Vector2) {
-	comp.position.Offset = offset
+func linesFittingInHeight(font atoms.Font, lines []textLine, lineHeight float32, paragraphSpacing float32, maxHeight float32) int {
+	var lineTop float32
+
+	for i, line := range lines {
+		if lineTop+font.LineHeight() > maxHeight {
+			if i == 0 {
+				return 1
+			}
+
+			return i
+		}
+
+		lineTop += calculateLineAdvance(line, lineHeight, paragraphSpacing)
+	}
+
+	return len(lines)
 }
 
-func (comp *TextComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) rl.Vector2 {
-	font, err := getFont(comp.fontName)
+func (comp *TextComponent) CalculateSize(getFont GetFontCallback, maxViewport rl.Vector2) (rl.Vector2, error) {
+	fontChain, err := comp.getFontChain(getFont)
 	if err != nil {
-		panic(fmt.Sprintf("Provided font (%s) is not loaded into memory", comp.fontName))
+		return rl.Vector2Zero(), err
 	}
 
-	var calculatedSize rl.Vector2
+	raylibFont := atoms.NewRaylibFont(fontChain, comp.fontSize, comp.spacing)
 
-	if !comp.wrapText {
-		calculatedSize = rl.MeasureTextEx(font, comp.text, comp.fontSize, comp.spacing)
-		comp.processedText = comp.text
+	lineHeight := comp.calculateLineHeight()
+
+	var lines []textLine
 
-		return calculatedSize
+	if !comp.wrapText {
+		lines = splitIntoTextLines(raylibFont, comp.text)
 	} else {
-		comp.processedText, calculatedSize = wrapText(atoms.NewRaylibFont(font, comp.fontSize, comp.spacing), comp.text, maxViewport.X)
+		lines = wrapText(raylibFont, comp.text, maxViewport.X)
+	}
 
-		return calculatedSize
+	comp.truncated = false
+
+	if !comp.wrapText {
+		for i, line := range lines {
+			truncatedLine, lineTruncated := truncateLine(raylibFont, line.text, comp.overflow, comp.ellipsis, maxViewport.X)
+
+			if lineTruncated {
+				lines[i] = newTextLine(raylibFont, truncatedLine, line.endsParagraph)
+				comp.truncated = true
+			}
+		}
 	}
+
+	maxLines := comp.maxLines
+
+	if comp.overflow != OverflowVisible {
+		fittingLines := linesFittingInHeight(raylibFont, lines, lineHeight, comp.paragraphSpacing, maxViewport.Y)
+
+		if maxLines == 0 || fittingLines < maxLines {
+			maxLines = fittingLines
+		}
+	}
+
+	lines, linesTruncated := limitLines(raylibFont, lines, maxLines, comp.overflow, comp.ellipsis, maxViewport.X)
+	comp.truncated = comp.truncated || linesTruncated
+
+	lines = comp.orderLinesVisually(raylibFont, lines)
+
+	comp.lines = lines
+	comp.processedText = joinTextLines(lines)
+
+	calculatedSize := calculateLinesSize(raylibFont, lines, lineHeight, comp.paragraphSpacing)
+
+	comp.size = calculatedSize
+
+	return calculatedSize, nil
 }
 
-func (comp *TextComponent) Render(getFont GetFontCallback) {
-	font, err := getFont(comp.fontName)
+func (comp *TextComponent) getFontChain(getFont GetFontCallback) (*atoms.FontChain, error) {
+	fontChain, err := getFont(comp.fontName, comp.fontWeight, comp.fontStyle)
 	if err != nil {
-		panic(fmt.Sprintf("Provided font (%s) is not loaded into memory", comp.fontName))
+		return nil, fmt.Errorf("%w: provided font (%s) can't be used: %w", ErrFontNotLoaded, comp.fontName, err)
 	}
 
-	rl.DrawTextEx(font, comp.processedText, comp.position.Calculate(), comp.fontSize, comp.spacing, comp.color)
+	return fontChain, nil
+}
+
+// orderLinesVisually reorders characters of every line from the logical to the visual order,
+// using the direction of the paragraph the line belongs to.
+func (comp *TextComponent) orderLinesVisually(font atoms.Font, lines []textLine) []textLine {
+	visualLines := make([]textLine, len(lines))
+	paragraphStart := 0
+
+	for i, line := range lines {
+		if !line.endsParagraph && i != len(lines)-1 {
+			continue
+		}
+
+		rightToLeft := comp.textDirection == TextDirectionRTL
+
+		if comp.textDirection == TextDirectionAuto {
+			rightToLeft, _ = detectTextDirection(joinTextLines(lines[paragraphStart : i+1]))
+		}
+
+		for j := paragraphStart; j <= i; j++ {
+			visualLines[j] = newTextLine(font, reorderLine(lines[j].text, rightToLeft), lines[j].endsParagraph)
+			visualLines[j].rightToLeft = rightToLeft
+		}
+
+		paragraphStart = i + 1
+	}
+
+	return visualLines
+}
+
+func (comp *TextComponent) calculateLineOffset(line textLine) float32 {
+	alignRight := false
+
+	switch comp.textAlign {
+	case TextAlignCenter:
+		return (comp.size.X - line.width) / 2
+	case TextAlignRight:
+		alignRight = true
+	case TextAlignStart, TextAlignJustify:
+		alignRight = line.rightToLeft
+	case TextAlignEnd:
+		alignRight = !line.rightToLeft
+	}
+
+	if alignRight {
+		return comp.size.X - line.width
+	}
+
+	return 0
+}
+
+// drawText draws text in runs of characters, which are found in the same glyph atlas.
+func (comp *TextComponent) drawText(fontChain *atoms.FontChain, raylibFont atoms.Font, text string, position rl.Vector2) {
+	runes := []rune(text)
+	runStart := 0
+
+	for runStart < len(runes) {
+		font := fontChain.GetGlyphFont(runes[runStart], comp.fontSize)
+		runEnd := runStart + 1
+
+		for runEnd < len(runes) && fontChain.GetGlyphFont(runes[runEnd], comp.fontSize).Texture.ID == font.Texture.ID {
+			runEnd++
+		}
+
+		run := string(runes[runStart:runEnd])
+
+		// All characters of the run come from the same font source, as they share the atlas.
+		if source, _ := fontChain.FindSource(runes[runStart]); source.IsSDF() {
+			fontChain.BeginSDFMode(comp.fontSize, comp.effects)
+			rl.DrawTextEx(font, run, position, comp.fontSize, comp.spacing, comp.color)
+			fontChain.EndSDFMode()
+		} else {
+			rl.DrawTextEx(font, run, position, comp.fontSize, comp.spacing, comp.color)
+		}
+
+		// The next run starts after the spacing which follows the last glyph of this one.
+		position.X += measureText(raylibFont, run) + comp.spacing
+		runStart = runEnd
+	}
+}
+
+func (comp *TextComponent) renderJustifiedLine(fontChain *atoms.FontChain, raylibFont atoms.Font, line textLine, linePosition rl.Vector2) {
+	words := strings.Split(line.text, " ")
+
+	if len(words) < 2 {
+		comp.drawText(fontChain, raylibFont, line.text, linePosition)
+		return
+	}
+
+	extraSpace := (comp.size.X - line.width) / float32(len(words)-1)
+	lineStart := linePosition.X
+	wordStart := 0
+
+	for i, word := range words {
+		if i > 0 {
+			// The word starts where it would be in the measured line, moved by the extra space of the gaps before it.
+			linePosition.X = lineStart + measureText(raylibFont, line.text[:wordStart]) + comp.spacing + extraSpace*float32(i)
+		}
+
+		comp.drawText(fontChain, raylibFont, word, linePosition)
+		wordStart += len(word) + 1
+	}
+}
+
+func (comp *TextComponent) Render(getFont GetFontCallback) error {
+	fontChain, err := comp.getFontChain(getFont)
+	if err != nil {
+		return err
+	}
+
+	raylibFont := atoms.NewRaylibFont(fontChain, comp.fontSize, comp.spacing)
+
+	lineHeight := comp.calculateLineHeight()
+
+	position := comp.position.Calculate()
+
+	for _, line := range comp.lines {
+		linePosition := rl.Vector2{X: position.X + comp.calculateLineOffset(line), Y: position.Y}
+
+		// The last line of a paragraph is never stretched, the same way as in text editors.
+		if comp.textAlign == TextAlignJustify && !line.endsParagraph {
+			comp.renderJustifiedLine(fontChain, raylibFont, line, linePosition)
+		} else {
+			comp.drawText(fontChain, raylibFont, line.text, linePosition)
+		}
+
+		position.Y += calculateLineAdvance(line, lineHeight, comp.paragraphSpacing)
+	}
+
+	return nil
+}
+
+func (comp *TextComponent) SetPosition(pos rl.Vector2) {
+	comp.position.Position = pos
+}
+
+func (comp *TextComponent) SetPositionOffset(offset rl.Vector2) {
+	comp.position.Offset = offset
+}
+
+// GetSize returns the size calculated by the last layout.
+func (comp *TextComponent) GetSize() rl.Vector2 {
+	return comp.size
 }
 
 func (comp *TextComponent) GetPosition() rl.Vector2 {
@@ -178,3 +719,8 @@ func (comp *TextComponent) GetPosition() rl.Vector2 {
 func (comp *TextComponent) GetEventBus() *atoms.EventBus {
 	return comp.eventBus
 }
+
+// GetTypeName is matched by type selectors of style sheets.
+func (comp *TextComponent) GetTypeName() string {
+	return "Text"
+}
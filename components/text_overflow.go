@@ -0,0 +1,149 @@
+package components
+
+import (
+	"strings"
+
+	"domanscy.group/gui/components/atoms"
+)
+
+const OverflowVisible = 0
+const OverflowClip = 1
+const OverflowEllipsis = 2
+const OverflowEllipsisMiddle = 3
+
+const DefaultEllipsis = "..."
+
+// measureText returns the width of text drawn glyph by glyph, with the font's spacing between every two glyphs.
+func measureText(font atoms.Font, text string) float32 {
+	var width float32
+
+	for i, character := range text {
+		if i > 0 {
+			width += font.Spacing()
+		}
+
+		width += font.GlyphWidth(character)
+	}
+
+	return width
+}
+
+// ellipsizeEnd always appends the ellipsis, cutting as many characters from the end of text as needed to fit into maxWidth.
+// Every kept character takes its glyph and the spacing after it, up to the ellipsis.
+func ellipsizeEnd(font atoms.Font, text string, ellipsis string, maxWidth float32) string {
+	available := maxWidth - measureText(font, ellipsis)
+
+	var prefix strings.Builder
+	var prefixWidth float32
+
+	for _, character := range text {
+		glyphWidth := font.GlyphWidth(character) + font.Spacing()
+
+		if prefixWidth+glyphWidth > available {
+			break
+		}
+
+		prefix.WriteRune(character)
+		prefixWidth += glyphWidth
+	}
+
+	return strings.TrimRight(prefix.String(), " ") + ellipsis
+}
+
+// ellipsizeMiddle keeps characters from both ends of text, which is what you want for file paths.
+func ellipsizeMiddle(font atoms.Font, text string, ellipsis string, maxWidth float32) string {
+	available := maxWidth - measureText(font, ellipsis)
+
+	runes := []rune(text)
+	head := 0
+	tail := len(runes)
+
+	var headWidth float32
+	var tailWidth float32
+
+	for head < tail {
+		if headWidth <= tailWidth {
+			glyphWidth := font.GlyphWidth(runes[head]) + font.Spacing()
+			if headWidth+tailWidth+glyphWidth > available {
+				break
+			}
+
+			headWidth += glyphWidth
+			head++
+		} else {
+			glyphWidth := font.GlyphWidth(runes[tail-1]) + font.Spacing()
+			if headWidth+tailWidth+glyphWidth > available {
+				break
+			}
+
+			tailWidth += glyphWidth
+			tail--
+		}
+	}
+
+	return string(runes[:head]) + ellipsis + string(runes[tail:])
+}
+
+func clipLine(font atoms.Font, text string, maxWidth float32) string {
+	var width float32
+
+	for i, character := range text {
+		if i > 0 {
+			width += font.Spacing()
+		}
+
+		width += font.GlyphWidth(character)
+
+		if width > maxWidth {
+			return text[:i]
+		}
+	}
+
+	return text
+}
+
+// truncateLine shortens a single line so it fits into maxWidth, according to the overflow mode.
+func truncateLine(font atoms.Font, line string, overflow int, ellipsis string, maxWidth float32) (truncatedLine string, truncated bool) {
+	if overflow == OverflowVisible || measureText(font, line) <= maxWidth {
+		return line, false
+	}
+
+	switch overflow {
+	case OverflowClip:
+		return clipLine(font, line, maxWidth), true
+	case OverflowEllipsis:
+		return ellipsizeEnd(font, line, ellipsis, maxWidth), true
+	case OverflowEllipsisMiddle:
+		return ellipsizeMiddle(font, line, ellipsis, maxWidth), true
+	default:
+		return line, false
+	}
+}
+
+// limitLines drops the lines past maxLines and marks the last visible line as cut off.
+func limitLines(font atoms.Font, lines []textLine, maxLines int, overflow int, ellipsis string, maxWidth float32) (visibleLines []textLine, truncated bool) {
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return lines, false
+	}
+
+	visibleLines = append([]textLine{}, lines[:maxLines]...)
+	lastLineIndex := maxLines - 1
+	lastLineText := visibleLines[lastLineIndex].text
+
+	switch overflow {
+	case OverflowEllipsis:
+		lastLineText = ellipsizeEnd(font, lastLineText, ellipsis, maxWidth)
+	case OverflowEllipsisMiddle:
+		rest := make([]string, 0, len(lines)-lastLineIndex)
+
+		for _, line := range lines[lastLineIndex:] {
+			rest = append(rest, line.text)
+		}
+
+		lastLineText, _ = truncateLine(font, strings.Join(rest, " "), OverflowEllipsisMiddle, ellipsis, maxWidth)
+	}
+
+	visibleLines[lastLineIndex] = newTextLine(font, lastLineText, true)
+
+	return visibleLines, true
+}
@@ -1,8 +1,10 @@
 package components
 
 import (
+	"fmt"
 	"testing"
 
+	"domanscy.group/gui/components/atoms"
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -25,6 +27,11 @@ func (TestFont) Spacing() float32 {
 	return 1
 }
 
+// testLineWidth is the width of a line of glyphCount glyphs of TestFont, including the spacing between them.
+func testLineWidth(glyphCount int) float32 {
+	return 32*float32(glyphCount) + float32(glyphCount-1)
+}
+
 func TestWrapText(t *testing.T) {
 	t.Run("Scenario 1", func(t *testing.T) {
 		// "Hello world! How are you today? Hello world! How are you today? Hello world! How are you today?"
@@ -33,11 +40,13 @@ func TestWrapText(t *testing.T) {
 
 		expectedText := "Hello\nworld\n,\nhow\nare\nyou\ntoday\n?"
 		expectedSize := rl.Vector2{
-			X: (32 * 5),
+			X: testLineWidth(5),
 			Y: (32 * 8) + (2 * 7), // there is text spacing between each line
 		}
 
-		processedText, size := wrapText(testFont, "Hello world, how are you today?", 32*5) // max 5 characters in one line
+		lines := wrapText(testFont, "Hello world, how are you today?", testLineWidth(5)) // max 5 characters in one line
+		processedText := joinTextLines(lines)
+		size := calculateLinesSize(testFont, lines, 32+DefaultLineSpacing, 0)
 
 		if processedText != expectedText {
 			t.Errorf("Processed text expected: \"%s\", received: \"%s\"", expectedText, processedText)
@@ -48,3 +57,204 @@ func TestWrapText(t *testing.T) {
 		}
 	})
 }
+
+func TestTruncateLine(t *testing.T) {
+	testCases := []struct {
+		overflow     int
+		expectedText string
+	}{
+		{OverflowVisible, "/home/user/file.txt"},
+		{OverflowClip, "/home/user"},
+		{OverflowEllipsis, "/home/u..."},
+		{OverflowEllipsisMiddle, "/hom...txt"},
+	}
+
+	testFont := TestFont{}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("Overflow %d", testCase.overflow), func(t *testing.T) {
+			line, _ := truncateLine(testFont, "/home/user/file.txt", testCase.overflow, DefaultEllipsis, testLineWidth(10)) // max 10 characters in one line
+
+			if line != testCase.expectedText {
+				t.Errorf("Truncated text expected: \"%s\", received: \"%s\"", testCase.expectedText, line)
+			}
+		})
+	}
+}
+
+func TestLimitLines(t *testing.T) {
+	testFont := TestFont{}
+
+	lines, truncated := limitLines(testFont, splitIntoTextLines(testFont, "Hello\nworld,\nhow"), 2, OverflowEllipsis, DefaultEllipsis, testLineWidth(6))
+
+	if !truncated {
+		t.Errorf("Expected lines to be truncated")
+	}
+
+	if processedText := joinTextLines(lines); processedText != "Hello\nwor..." {
+		t.Errorf("Processed text expected: \"Hello\nwor...\", received: \"%s\"", processedText)
+	}
+
+	lines, truncated = limitLines(testFont, splitIntoTextLines(testFont, "Hello\nworld"), 2, OverflowEllipsis, DefaultEllipsis, testLineWidth(6))
+
+	if truncated || len(lines) != 2 {
+		t.Errorf("Expected lines not to be truncated, received: \"%s\"", joinTextLines(lines))
+	}
+}
+
+func TestWrapTextLineMetrics(t *testing.T) {
+	testFont := TestFont{}
+
+	lines := wrapText(testFont, "Hello world\nhow are you?", testLineWidth(9))
+
+	expectedLines := []textLine{
+		{"Hello", testLineWidth(5), false, false},
+		{"world", testLineWidth(5), true, false},
+		{"how are", testLineWidth(7), false, false},
+		{"you?", testLineWidth(4), true, false},
+	}
+
+	if len(lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines, received %d: \"%s\"", len(expectedLines), len(lines), joinTextLines(lines))
+	}
+
+	for i, expectedLine := range expectedLines {
+		if lines[i] != expectedLine {
+			t.Errorf("Line %d expected: %+v, received: %+v", i, expectedLine, lines[i])
+		}
+	}
+}
+
+func TestCalculateLinesSize(t *testing.T) {
+	testFont := TestFont{}
+
+	lines := wrapText(testFont, "Hello world\nhow are you?", testLineWidth(9))
+
+	expectedSize := rl.Vector2{
+		X: testLineWidth(7),
+		Y: 48 + (48 + 10) + 48 + 32, // the last line takes only the height of its glyphs
+	}
+
+	size := calculateLinesSize(testFont, lines, 48, 10)
+
+	if !rl.Vector2Equals(size, expectedSize) {
+		t.Errorf("Expected size X: %f Y: %f, received: X: %f Y: %f", expectedSize.X, expectedSize.Y, size.X, size.Y)
+	}
+
+	if fittingLines := linesFittingInHeight(testFont, lines, 48, 10, 130); fittingLines != 2 {
+		t.Errorf("Expected 2 lines to fit, received %d", fittingLines)
+	}
+}
+
+func TestWrapTextLineBreaking(t *testing.T) {
+	testCases := []struct {
+		name         string
+		text         string
+		maxWidth     float32
+		expectedText string
+	}{
+		{"CJK", "日本語のテキスト", testLineWidth(3), "日本語\nのテキ\nスト"},
+		{"Small kana", "ネコキャット", testLineWidth(3), "ネコ\nキャッ\nト"},
+		{"Closing punctuation", "こんにちは。", testLineWidth(5), "こんにち\nは。"},
+		{"Non-breaking space", "100\u00A0km ab", testLineWidth(6), "100\u00A0km\nab"},
+		{"Soft hyphen", "hyphen\u00ADation", testLineWidth(7), "hyphen-\nation"},
+		{"Soft hyphen without room for the hyphen", "hyphen\u00ADation", testLineWidth(6), "hyphen\nation"},
+		{"Soft hyphen without break", "hyphen\u00ADation", testLineWidth(12), "hyphenation"},
+		{"Hyphen", "well-known", testLineWidth(7), "well-\nknown"},
+		{"Tab", "a\tb", testLineWidth(10), "a    b"},
+		{"Glyph wider than line", "abc", 16, "a\nb\nc"},
+		{"Carriage return", "a\r\nb", testLineWidth(5), "a\nb"},
+		{"Empty text", "", testLineWidth(5), ""},
+	}
+
+	testFont := TestFont{}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			processedText := joinTextLines(wrapText(testFont, testCase.text, testCase.maxWidth))
+
+			if processedText != testCase.expectedText {
+				t.Errorf("Processed text expected: %q, received: %q", testCase.expectedText, processedText)
+			}
+		})
+	}
+}
+
+func TestReorderLine(t *testing.T) {
+	testCases := []struct {
+		name         string
+		text         string
+		rightToLeft  bool
+		expectedText string
+	}{
+		{"Left-to-right", "hello world", false, "hello world"},
+		{"Right-to-left", "שלום", true, "םולש"},
+		{"Embedded right-to-left", "hello שלום world", false, "hello םולש world"},
+		{"Embedded left-to-right", "שלום hello", true, "hello םולש"},
+		{"Numbers", "שלום 123", true, "123 םולש"},
+		{"Mirrored brackets", "(שלום)", true, "(םולש)"},
+		{"Arabic", "مرحبا 42", true, "42 ابحرم"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			visualText := reorderLine(testCase.text, testCase.rightToLeft)
+
+			if visualText != testCase.expectedText {
+				t.Errorf("Visual text expected: %q, received: %q", testCase.expectedText, visualText)
+			}
+		})
+	}
+}
+
+func TestTextPositioning(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	// Empty wrapped texts aren't measured by raylib, so the layout can be calculated without loaded fonts.
+	getFont := func(fontName string, fontWeight int, fontStyle int) (*atoms.FontChain, error) {
+		return atoms.NewFontChain(nil, nil), nil
+	}
+
+	text1 := NewTextComponent(eventBus, "", "Roboto", 32, 0, rl.White)
+	text2 := NewTextComponent(eventBus, "", "Roboto", 32, 0, rl.White)
+	text3 := NewTextComponent(eventBus, "", "Roboto", 32, 0, rl.White)
+
+	for _, text := range []*TextComponent{text1, text2, text3} {
+		text.SetWrapText(true)
+	}
+
+	rectangle, err := NewRectangleComponent(eventBus, text3, rl.Blank, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rectangle.SetPaddingTop(5)
+	rectangle.SetPaddingLeft(10)
+
+	layout, err := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layout.AddChild(text1)
+	layout.AddChild(text2)
+	layout.AddChild(rectangle)
+
+	if _, err := layout.CalculateSize(getFont, rl.Vector2{X: 800, Y: 600}); err != nil {
+		t.Fatalf("Couldn't calculate the layout: %s", err)
+	}
+
+	layout.SetPosition(rl.Vector2{X: 100, Y: 200})
+
+	expectPosition := func(name string, text *TextComponent, expected rl.Vector2) {
+		t.Helper()
+
+		if position := text.GetPosition(); position != expected {
+			t.Errorf("%s was expected at %v, got %v", name, expected, position)
+		}
+	}
+
+	expectPosition("text1", text1, rl.Vector2{X: 100, Y: 200})
+	expectPosition("text2", text2, rl.Vector2{X: 100, Y: 232})
+	expectPosition("text3", text3, rl.Vector2{X: 110, Y: 269})
+}
@@ -0,0 +1,270 @@
+package components
+
+import (
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Tokens of the themes shipped with the library, apps can add their own ones.
+const ColorBackground = "background"
+const ColorSurface = "surface"
+const ColorText = "text"
+const ColorTextMuted = "text-muted"
+const ColorPrimary = "primary"
+const ColorOnPrimary = "on-primary"
+const ColorBorder = "border"
+const ColorError = "error"
+
+// Font family tokens aren't set by the shipped themes, because fonts are added by the app, see AppBuilder.WithFont.
+const FontFamilyBody = "body"
+const FontFamilyHeading = "heading"
+
+const SizeSmall = "small"
+const SizeBody = "body"
+const SizeLarge = "large"
+const SizeHeading = "heading"
+
+const SpacingSmall = "small"
+const SpacingMedium = "medium"
+const SpacingLarge = "large"
+
+// Radii are roundness values of RectangleComponent, from 0 to 1.
+const RadiusNone = "none"
+const RadiusSmall = "small"
+const RadiusMedium = "medium"
+const RadiusLarge = "large"
+
+var ThemeChangedEvent = atoms.NewEvent[*Theme]("gui:theme-changed")
+
+// Theme maps named tokens to values. Tokens missing in a theme are looked up in the theme it extends.
+type Theme struct {
+	name string
+	base *Theme
+
+	colors       map[string]rl.Color
+	fontFamilies map[string]string
+	sizes        map[string]float32
+	spacing      map[string]float32
+	radii        map[string]float32
+}
+
+// NewTheme creates an empty theme, base can be nil.
+func NewTheme(name string, base *Theme) *Theme {
+	return &Theme{
+		name: name,
+		base: base,
+
+		colors:       map[string]rl.Color{},
+		fontFamilies: map[string]string{},
+		sizes:        map[string]float32{},
+		spacing:      map[string]float32{},
+		radii:        map[string]float32{},
+	}
+}
+
+// DefaultTheme is the light theme.
+func DefaultTheme() *Theme {
+	return LightTheme()
+}
+
+func LightTheme() *Theme {
+	return NewTheme("light", nil).
+		WithColor(ColorBackground, rl.NewColor(245, 245, 245, 255)).
+		WithColor(ColorSurface, rl.White).
+		WithColor(ColorText, rl.NewColor(28, 28, 30, 255)).
+		WithColor(ColorTextMuted, rl.NewColor(110, 110, 115, 255)).
+		WithColor(ColorPrimary, rl.NewColor(0, 102, 204, 255)).
+		WithColor(ColorOnPrimary, rl.White).
+		WithColor(ColorBorder, rl.NewColor(210, 210, 215, 255)).
+		WithColor(ColorError, rl.NewColor(200, 30, 30, 255)).
+		WithSize(SizeSmall, 12).
+		WithSize(SizeBody, 16).
+		WithSize(SizeLarge, 20).
+		WithSize(SizeHeading, 32).
+		WithSpacing(SpacingSmall, 4).
+		WithSpacing(SpacingMedium, 8).
+		WithSpacing(SpacingLarge, 16).
+		WithRadius(RadiusNone, 0).
+		WithRadius(RadiusSmall, 0.1).
+		WithRadius(RadiusMedium, 0.25).
+		WithRadius(RadiusLarge, 0.5)
+}
+
+func DarkTheme() *Theme {
+	return NewTheme("dark", LightTheme()).
+		WithColor(ColorBackground, rl.NewColor(18, 18, 20, 255)).
+		WithColor(ColorSurface, rl.NewColor(36, 36, 40, 255)).
+		WithColor(ColorText, rl.NewColor(235, 235, 240, 255)).
+		WithColor(ColorTextMuted, rl.NewColor(150, 150, 160, 255)).
+		WithColor(ColorPrimary, rl.NewColor(90, 170, 255, 255)).
+		WithColor(ColorOnPrimary, rl.Black).
+		WithColor(ColorBorder, rl.NewColor(60, 60, 66, 255)).
+		WithColor(ColorError, rl.NewColor(255, 100, 100, 255))
+}
+
+// HighContrastTheme uses pure colors and bigger text.
+func HighContrastTheme() *Theme {
+	return NewTheme("high-contrast", LightTheme()).
+		WithColor(ColorBackground, rl.Black).
+		WithColor(ColorSurface, rl.Black).
+		WithColor(ColorText, rl.White).
+		WithColor(ColorTextMuted, rl.White).
+		WithColor(ColorPrimary, rl.Yellow).
+		WithColor(ColorOnPrimary, rl.Black).
+		WithColor(ColorBorder, rl.White).
+		WithColor(ColorError, rl.NewColor(255, 80, 80, 255)).
+		WithSize(SizeSmall, 16).
+		WithSize(SizeBody, 20).
+		WithSize(SizeLarge, 24).
+		WithSize(SizeHeading, 40)
+}
+
+func (theme *Theme) GetName() string {
+	return theme.name
+}
+
+func (theme *Theme) WithColor(token string, color rl.Color) *Theme {
+	theme.colors[token] = color
+	return theme
+}
+
+func (theme *Theme) WithFontFamily(token string, fontName string) *Theme {
+	theme.fontFamilies[token] = fontName
+	return theme
+}
+
+func (theme *Theme) WithSize(token string, size float32) *Theme {
+	theme.sizes[token] = size
+	return theme
+}
+
+func (theme *Theme) WithSpacing(token string, spacing float32) *Theme {
+	theme.spacing[token] = spacing
+	return theme
+}
+
+func (theme *Theme) WithRadius(token string, radius float32) *Theme {
+	theme.radii[token] = radius
+	return theme
+}
+
+func (theme *Theme) GetColor(token string) (rl.Color, bool) {
+	return lookupToken(theme, token, func(theme *Theme) map[string]rl.Color { return theme.colors })
+}
+
+func (theme *Theme) GetFontFamily(token string) (string, bool) {
+	return lookupToken(theme, token, func(theme *Theme) map[string]string { return theme.fontFamilies })
+}
+
+func (theme *Theme) GetSize(token string) (float32, bool) {
+	return lookupToken(theme, token, func(theme *Theme) map[string]float32 { return theme.sizes })
+}
+
+func (theme *Theme) GetSpacing(token string) (float32, bool) {
+	return lookupToken(theme, token, func(theme *Theme) map[string]float32 { return theme.spacing })
+}
+
+func (theme *Theme) GetRadius(token string) (float32, bool) {
+	return lookupToken(theme, token, func(theme *Theme) map[string]float32 { return theme.radii })
+}
+
+func lookupToken[T any](theme *Theme, token string, values func(theme *Theme) map[string]T) (T, bool) {
+	for ; theme != nil; theme = theme.base {
+		if value, ok := values(theme)[token]; ok {
+			return value, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// ThemeProvider holds the current theme. Components bound to its tokens are updated when the theme is switched,
+// colors in the next frame and sizes after a relayout.
+type ThemeProvider struct {
+	eventBus *atoms.EventBus
+	current  *atoms.Signal[*Theme]
+}
+
+func NewThemeProvider(eventBus *atoms.EventBus, theme *Theme) *ThemeProvider {
+	return &ThemeProvider{
+		eventBus: eventBus,
+		current:  atoms.NewSignal(theme),
+	}
+}
+
+func (provider *ThemeProvider) GetTheme() *Theme {
+	return provider.current.Peek()
+}
+
+// SetTheme switches the theme, it has to be called on the UI thread like other changes of components.
+func (provider *ThemeProvider) SetTheme(theme *Theme) {
+	if provider.current.Peek() == theme {
+		return
+	}
+
+	provider.current.Set(theme)
+	atoms.Publish(provider.eventBus, ThemeChangedEvent, theme)
+}
+
+// Color returns the value of the token in the current theme, tokens missing in the theme keep the last known value.
+// Components bound to a token which no theme has defined yet keep their own value.
+func (provider *ThemeProvider) Color(token string) atoms.ReadonlySignal[rl.Color] {
+	return newThemeToken(provider, (*Theme).GetColor, token)
+}
+
+func (provider *ThemeProvider) FontFamily(token string) atoms.ReadonlySignal[string] {
+	return newThemeToken(provider, (*Theme).GetFontFamily, token)
+}
+
+func (provider *ThemeProvider) Size(token string) atoms.ReadonlySignal[float32] {
+	return newThemeToken(provider, (*Theme).GetSize, token)
+}
+
+func (provider *ThemeProvider) Spacing(token string) atoms.ReadonlySignal[float32] {
+	return newThemeToken(provider, (*Theme).GetSpacing, token)
+}
+
+func (provider *ThemeProvider) Radius(token string) atoms.ReadonlySignal[float32] {
+	return newThemeToken(provider, (*Theme).GetRadius, token)
+}
+
+type themeTokenValue[T comparable] struct {
+	value T
+	found bool
+}
+
+// themeToken is a signal of a token in the current theme. Until the token is found, its value is T's zero value,
+// which bindings don't apply, see optionalSignal.
+type themeToken[T comparable] struct {
+	computed *atoms.Computed[themeTokenValue[T]]
+}
+
+func newThemeToken[T comparable](provider *ThemeProvider, lookup func(theme *Theme, token string) (T, bool), token string) *themeToken[T] {
+	last := themeTokenValue[T]{}
+
+	return &themeToken[T]{
+		computed: atoms.NewComputed(func() themeTokenValue[T] {
+			if value, ok := lookup(provider.current.Get(), token); ok {
+				last = themeTokenValue[T]{value, true}
+			}
+
+			return last
+		}),
+	}
+}
+
+func (token *themeToken[T]) Get() T {
+	return token.computed.Get().value
+}
+
+func (token *themeToken[T]) Peek() T {
+	return token.computed.Peek().value
+}
+
+func (token *themeToken[T]) getOptional() (T, bool) {
+	tokenValue := token.computed.Get()
+
+	return tokenValue.value, tokenValue.found
+}
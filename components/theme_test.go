@@ -0,0 +1,99 @@
+package components
+
+import (
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestThemes(t *testing.T) {
+	t.Run("Tokens missing in a theme come from its base", func(t *testing.T) {
+		theme := NewTheme("brand", DarkTheme()).WithColor(ColorPrimary, rl.Magenta)
+
+		if color, _ := theme.GetColor(ColorPrimary); color != rl.Magenta {
+			t.Errorf("Expected the brand primary color, got %v", color)
+		}
+
+		if size, ok := theme.GetSize(SizeBody); !ok || size != 16 {
+			t.Errorf("Expected the body size of the light theme, got %f", size)
+		}
+
+		if _, ok := theme.GetFontFamily(FontFamilyBody); ok {
+			t.Error("Expected no font family in the shipped themes")
+		}
+	})
+
+	t.Run("Switching the theme updates bound components", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		themes := NewThemeProvider(eventBus, DefaultTheme().WithFontFamily(FontFamilyBody, "Roboto"))
+
+		changes := []string{}
+		atoms.Subscribe(eventBus, ThemeChangedEvent, func(theme *Theme) {
+			changes = append(changes, theme.GetName())
+		})
+
+		recalculations := 0
+		atoms.Subscribe(eventBus, ScheduleRecalculationEvent, func(struct{}) {
+			recalculations++
+		})
+
+		text := NewTextComponent(eventBus, "Hello", "", 10, 0, rl.Blank)
+		text.BindColor(themes.Color(ColorText))
+		text.BindFontName(themes.FontFamily(FontFamilyBody))
+		text.BindFontSize(themes.Size(SizeBody))
+
+		card, _ := NewRectangleComponent(eventBus, text, rl.Blank, 0)
+		card.BindBackgroundColor(themes.Color(ColorSurface))
+		card.BindPadding(themes.Spacing(SpacingMedium))
+		card.BindRoundness(themes.Radius(RadiusSmall))
+
+		if text.fontName != "Roboto" || text.fontSize != 16 || card.GetPaddingTop() != 8 || card.roundness != 0.1 {
+			t.Errorf("Expected the tokens of the default theme, got %s %f %f %f", text.fontName, text.fontSize, card.GetPaddingTop(), card.roundness)
+		}
+
+		recalculations = 0
+		themes.SetTheme(DarkTheme())
+
+		if expected, _ := DarkTheme().GetColor(ColorText); text.GetColor() != expected {
+			t.Errorf("Expected the dark text color, got %v", text.GetColor())
+		}
+
+		if expected, _ := DarkTheme().GetColor(ColorSurface); card.GetBackgroundColor() != expected {
+			t.Errorf("Expected the dark surface color, got %v", card.GetBackgroundColor())
+		}
+
+		// The dark theme has no font family, so the last one stays, and its sizes are the same as in the light one.
+		if text.fontName != "Roboto" || recalculations != 0 {
+			t.Errorf("Expected only colors to change, got font %s and %d relayouts", text.fontName, recalculations)
+		}
+
+		themes.SetTheme(HighContrastTheme())
+
+		if text.fontSize != 20 || recalculations != 1 {
+			t.Errorf("Expected a bigger font after a single relayout, got %f and %d relayouts", text.fontSize, recalculations)
+		}
+
+		if len(changes) != 2 || changes[0] != "dark" || changes[1] != "high-contrast" {
+			t.Errorf("Expected two theme changes, got %v", changes)
+		}
+	})
+
+	t.Run("Tokens missing in every theme don't change bound components", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		themes := NewThemeProvider(eventBus, DefaultTheme())
+
+		text := NewTextComponent(eventBus, "Hello", "Roboto", 10, 0, rl.Blank)
+		text.BindFontName(themes.FontFamily(FontFamilyHeading))
+
+		if text.fontName != "Roboto" {
+			t.Errorf("Expected the font of the text to stay, got %q", text.fontName)
+		}
+
+		themes.SetTheme(DarkTheme().WithFontFamily(FontFamilyHeading, "Inter"))
+
+		if text.fontName != "Inter" {
+			t.Errorf("Expected the font of the new theme, got %q", text.fontName)
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package components
+
+import (
+	"slices"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// ComponentNode gives a component its place in the tree: a parent, an ID and tags. It's embedded into components,
+// so their parents are set by containers when they are attached.
+type ComponentNode struct {
+	id     string
+	tags   []string
+	states []string
+	parent Component
+
+	// style is set by the style sheet applied to the component.
+	style *nodeStyle
+}
+
+func NewComponentNode() ComponentNode {
+	return ComponentNode{
+		id:     "",
+		tags:   []string{},
+		states: []string{},
+		parent: nil,
+
+		style: nil,
+	}
+}
+
+// Container is implemented by components which have children, so hooks and tree utilities reach the whole tree.
+type Container interface {
+	GetChildren() []Component
+}
+
+// NodeComponent is implemented by components which embed ComponentNode.
+type NodeComponent interface {
+	Component
+	GetID() string
+	HasTag(tag string) bool
+	SetState(state string, active bool)
+	HasState(state string) bool
+	GetParent() Component
+
+	setParent(parent Component)
+	getStyle() *nodeStyle
+}
+
+func (node *ComponentNode) GetID() string {
+	return node.id
+}
+
+// SetID sets the ID used by FindByID, IDs are expected (but not required) to be unique in the tree.
+func (node *ComponentNode) SetID(id string) {
+	node.id = id
+}
+
+func (node *ComponentNode) AddTag(tag string) {
+	if !node.HasTag(tag) {
+		node.tags = append(node.tags, tag)
+	}
+}
+
+func (node *ComponentNode) RemoveTag(tag string) {
+	node.tags = slices.DeleteFunc(node.tags, func(other string) bool {
+		return other == tag
+	})
+}
+
+func (node *ComponentNode) HasTag(tag string) bool {
+	return slices.Contains(node.tags, tag)
+}
+
+func (node *ComponentNode) GetTags() []string {
+	return slices.Clone(node.tags)
+}
+
+// SetState turns a state like StateHover on or off, style sheets can target it with a selector like Rectangle:hover.
+// The app sets StateHover and StatePressed from the mouse input, the other states are up to the app code.
+func (node *ComponentNode) SetState(state string, active bool) {
+	node.states = slices.DeleteFunc(node.states, func(other string) bool {
+		return other == state
+	})
+
+	if active {
+		node.states = append(node.states, state)
+	}
+}
+
+func (node *ComponentNode) HasState(state string) bool {
+	return slices.Contains(node.states, state)
+}
+
+// GetParent returns the container the component is attached to, or nil for the root and detached components.
+func (node *ComponentNode) GetParent() Component {
+	return node.parent
+}
+
+func (node *ComponentNode) setParent(parent Component) {
+	node.parent = parent
+}
+
+func (node *ComponentNode) getStyle() *nodeStyle {
+	if node.style == nil {
+		node.style = newNodeStyle()
+	}
+
+	return node.style
+}
+
+// childDetacher is implemented by containers, so a child attached to another one is removed from them first.
+type childDetacher interface {
+	detachChild(child Component)
+}
+
+// detachFromParent removes the child from its container, so it's never in two places of the tree.
+func detachFromParent(child Component) {
+	nodeComponent, ok := child.(NodeComponent)
+	if !ok {
+		return
+	}
+
+	if parent, ok := nodeComponent.GetParent().(childDetacher); ok {
+		parent.detachChild(child)
+	}
+}
+
+func setParent(child Component, parent Component) {
+	if nodeComponent, ok := child.(NodeComponent); ok {
+		nodeComponent.setParent(parent)
+	}
+}
+
+// Walk visits the component and its descendants, parents before children, in the order of children.
+// The walk stops as soon as visit returns false.
+func Walk(root Component, visit func(component Component) bool) {
+	walk(root, visit)
+}
+
+func walk(component Component, visit func(component Component) bool) bool {
+	if !visit(component) {
+		return false
+	}
+
+	if container, ok := component.(Container); ok {
+		for _, child := range container.GetChildren() {
+			if !walk(child, visit) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// FindByID returns the first component with the ID, or nil.
+func FindByID(root Component, id string) Component {
+	var found Component
+
+	Walk(root, func(component Component) bool {
+		if nodeComponent, ok := component.(NodeComponent); ok && nodeComponent.GetID() == id {
+			found = component
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
+
+func FindByTag(root Component, tag string) []Component {
+	found := []Component{}
+
+	Walk(root, func(component Component) bool {
+		if nodeComponent, ok := component.(NodeComponent); ok && nodeComponent.HasTag(tag) {
+			found = append(found, component)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// FindByType returns all components of the type, e.g. FindByType[*TextComponent](root).
+func FindByType[T Component](root Component) []T {
+	found := []T{}
+
+	Walk(root, func(component Component) bool {
+		if typed, ok := component.(T); ok {
+			found = append(found, typed)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// Ancestors returns the parent of the component, its parent and so on up to the root.
+func Ancestors(component Component) []Component {
+	ancestors := []Component{}
+
+	for {
+		nodeComponent, ok := component.(NodeComponent)
+		if !ok || nodeComponent.GetParent() == nil {
+			return ancestors
+		}
+
+		component = nodeComponent.GetParent()
+		ancestors = append(ancestors, component)
+	}
+}
+
+// PathToRoot returns the component followed by its ancestors.
+func PathToRoot(component Component) []Component {
+	return append([]Component{component}, Ancestors(component)...)
+}
+
+// SizedComponent is implemented by components which know their size from the last layout, so they can be found by a point.
+type SizedComponent interface {
+	GetSize() rl.Vector2
+}
+
+// ComponentsAt returns the topmost component containing the point, followed by its ancestors, e.g. to find the hovered one.
+// Later children are drawn over the earlier ones, so they are checked first.
+func ComponentsAt(root Component, point rl.Vector2) []Component {
+	if container, ok := root.(Container); ok {
+		children := container.GetChildren()
+
+		for i := len(children) - 1; i >= 0; i-- {
+			if found := ComponentsAt(children[i], point); len(found) > 0 {
+				return append(found, root)
+			}
+		}
+	}
+
+	sized, ok := root.(SizedComponent)
+	if !ok {
+		return []Component{}
+	}
+
+	position := root.GetPosition()
+	size := sized.GetSize()
+
+	if point.X < position.X || point.Y < position.Y || point.X >= position.X+size.X || point.Y >= position.Y+size.Y {
+		return []Component{}
+	}
+
+	return []Component{root}
+}
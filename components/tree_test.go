@@ -0,0 +1,139 @@
+package components
+
+import (
+	"slices"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestTreeTraversal(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	title := NewTextComponent(eventBus, "Title", "Roboto", 32, 0, rl.White)
+	title.SetID("title")
+	title.AddTag("heading")
+
+	label := NewTextComponent(eventBus, "Label", "Roboto", 16, 0, rl.White)
+	label.AddTag("heading")
+	label.AddTag("heading")
+
+	card, _ := NewRectangleComponent(eventBus, label, rl.Blank, 0)
+	card.SetID("card")
+
+	root, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	root.AddChild(title)
+	root.AddChild(card)
+
+	t.Run("Parents are set when attaching", func(t *testing.T) {
+		if root.GetParent() != nil {
+			t.Errorf("Expected the root to have no parent, got %v", root.GetParent())
+		}
+
+		if title.GetParent() != root || card.GetParent() != root || label.GetParent() != card {
+			t.Error("Expected parents to be set by containers")
+		}
+
+		if !slices.Equal(PathToRoot(label), []Component{label, card, root}) {
+			t.Errorf("Unexpected path to root: %v", PathToRoot(label))
+		}
+
+		if !slices.Equal(Ancestors(title), []Component{root}) {
+			t.Errorf("Unexpected ancestors: %v", Ancestors(title))
+		}
+	})
+
+	t.Run("Walk visits parents first and can be stopped", func(t *testing.T) {
+		visited := []Component{}
+
+		Walk(root, func(component Component) bool {
+			visited = append(visited, component)
+			return component != card
+		})
+
+		if !slices.Equal(visited, []Component{root, title, card}) {
+			t.Errorf("Unexpected visited components: %v", visited)
+		}
+	})
+
+	t.Run("Components can be found by ID, tag and type", func(t *testing.T) {
+		if FindByID(root, "card") != card {
+			t.Error("Expected to find the card by its ID")
+		}
+
+		if FindByID(root, "missing") != nil {
+			t.Error("Expected no component for an unknown ID")
+		}
+
+		if !slices.Equal(FindByTag(root, "heading"), []Component{title, label}) {
+			t.Errorf("Unexpected components tagged as heading: %v", FindByTag(root, "heading"))
+		}
+
+		if !slices.Equal(FindByType[*TextComponent](root), []*TextComponent{title, label}) {
+			t.Errorf("Unexpected text components: %v", FindByType[*TextComponent](root))
+		}
+	})
+
+	t.Run("Parents are cleared when detaching", func(t *testing.T) {
+		card.RemoveChild()
+		label.RemoveTag("heading")
+
+		if label.GetParent() != nil || len(label.GetTags()) != 0 {
+			t.Error("Expected the label to be detached and without tags")
+		}
+
+		if err := root.RemoveChild(title); err != nil {
+			t.Fatal(err)
+		}
+
+		if title.GetParent() != nil {
+			t.Error("Expected the title to be detached")
+		}
+	})
+}
+
+func TestComponentsAt(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	newBox := func(width float32, height float32) *RectangleComponent {
+		box, _ := NewRectangleComponent(eventBus, nil, rl.Blank, 0)
+		box.SetPaddingRight(width)
+		box.SetPaddingBottom(height)
+
+		return box
+	}
+
+	first := newBox(100, 20)
+	second := newBox(50, 30)
+
+	card, _ := NewRectangleComponent(eventBus, second, rl.Blank, 0)
+	card.SetPaddingTop(10)
+
+	root, _ := NewLayoutComponent(eventBus, DirectionColumn, AlignStart, AlignStart)
+	root.AddChild(first)
+	root.AddChild(card)
+
+	if _, err := root.CalculateSize(nil, rl.NewVector2(800, 600)); err != nil {
+		t.Fatal(err)
+	}
+
+	root.SetPosition(rl.Vector2Zero())
+
+	testCases := []struct {
+		point    rl.Vector2
+		expected []Component
+	}{
+		{rl.NewVector2(10, 10), []Component{first, root}},
+		{rl.NewVector2(10, 25), []Component{card, root}},
+		{rl.NewVector2(49, 59), []Component{second, card, root}},
+		{rl.NewVector2(60, 59), []Component{root}},
+		{rl.NewVector2(10, 80), []Component{}},
+	}
+
+	for _, testCase := range testCases {
+		if found := ComponentsAt(root, testCase.point); !slices.Equal(found, testCase.expected) {
+			t.Errorf("Expected %v at %v, got %v", testCase.expected, testCase.point, found)
+		}
+	}
+}
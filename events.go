@@ -1,8 +1,48 @@
 package gui
 
-import rl "github.com/gen2brain/raylib-go/raylib"
+import (
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var WindowResizedEvent = atoms.NewEvent[WindowResizedEventArgs]("gui:window-resized")
+var MissingGlyphsEvent = atoms.NewEvent[MissingGlyphsEventArgs]("gui:missing-glyphs")
+var ErrorEvent = atoms.NewEvent[*ErrorEventArgs]("gui:error")
+var FrameEvent = atoms.NewEvent[FrameEventArgs]("gui:frame")
 
 type WindowResizedEventArgs struct {
-	oldWindowSize rl.Vector2
-	newWindowSize rl.Vector2
+	OldWindowSize rl.Vector2
+	NewWindowSize rl.Vector2
+}
+
+// FrameEventArgs are dispatched with gui:frame at the start of every frame, after the input events.
+// Time is the sum of frame deltas, so it is deterministic during a replay.
+type FrameEventArgs struct {
+	Frame int
+	Delta float32
+	Time  float64
+}
+
+type MissingGlyphsEventArgs struct {
+	FontName   string
+	FontWeight int
+	FontStyle  int
+	Codepoints []rune
+}
+
+const ErrorPhaseLayout = 0
+const ErrorPhaseRender = 1
+
+// ErrorEventArgs are dispatched with gui:error, when laying out or rendering of components fails, e.g. because of an unknown font.
+// Unless one of the listeners calls Recover, the window is closed and AppBuilder.Run returns the error.
+type ErrorEventArgs struct {
+	Err   error
+	Phase int
+
+	recovered bool
+}
+
+// Recover keeps the app running, the failed frame is drawn with the sizes calculated last time.
+func (args *ErrorEventArgs) Recover() {
+	args.recovered = true
 }
@@ -0,0 +1,126 @@
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+)
+
+type fontOptions struct {
+	sdf bool
+}
+
+type FontOption func(options *fontOptions)
+
+// AsSDF loads the font as a signed distance field, so it stays crisp at any scale and supports
+// outline and glow effects of TextComponent. Text is measured the same way as with bitmap fonts.
+func AsSDF() FontOption {
+	return func(options *fontOptions) {
+		options.sdf = true
+	}
+}
+
+// FontFile is a TrueType or OpenType font file, which is read when the app starts.
+type FontFile struct {
+	name string
+	read func() ([]byte, error)
+
+	// path is set only for files read from the disk, which can be watched in dev mode.
+	path string
+}
+
+func FontFromPath(path string) FontFile {
+	return FontFile{
+		name: path,
+		path: path,
+		read: func() ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+}
+
+// FontFromFS reads the font from a file system, e.g. embed.FS with fonts embedded into the binary.
+func FontFromFS(fileSystem fs.FS, path string) FontFile {
+	return FontFile{
+		name: path,
+		read: func() ([]byte, error) {
+			return fs.ReadFile(fileSystem, path)
+		},
+	}
+}
+
+// FontFromReader reads the whole font when the app starts, the reader isn't closed.
+func FontFromReader(reader io.Reader) FontFile {
+	return FontFile{
+		name: "reader",
+		read: func() ([]byte, error) {
+			return io.ReadAll(reader)
+		},
+	}
+}
+
+func FontFromBytes(data []byte) FontFile {
+	return FontFile{
+		name: "bytes",
+		read: func() ([]byte, error) {
+			return bytes.Clone(data), nil
+		},
+	}
+}
+
+type fontToLoad struct {
+	familyName string
+	weight     int
+	style      int
+	file       FontFile
+	options    fontOptions
+}
+
+// loadFontFamilies reads all fonts and groups them into families. Glyphs are rasterized later, when they are needed for the first time.
+func loadFontFamilies(fonts []fontToLoad) (map[string]*atoms.FontFamily, error) {
+	fontFamilies := map[string]*atoms.FontFamily{}
+
+	for _, font := range fonts {
+		fontSource, err := loadFontSource(font)
+		if err != nil {
+			return nil, err
+		}
+
+		fontFamily, ok := fontFamilies[font.familyName]
+
+		if !ok {
+			fontFamily = atoms.NewFontFamily()
+			fontFamilies[font.familyName] = fontFamily
+		}
+
+		fontFamily.AddVariant(font.weight, font.style == components.FontStyleItalic, fontSource)
+	}
+
+	return fontFamilies, nil
+}
+
+func loadFontSource(font fontToLoad) (*atoms.FontSource, error) {
+	fontData, err := font.file.read()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read font %s (%s): %w", font.familyName, font.file.name, err)
+	}
+
+	var fontSource *atoms.FontSource
+
+	if font.options.sdf {
+		fontSource, err = atoms.NewSDFFontSource(fontData)
+	} else {
+		fontSource, err = atoms.NewFontSource(fontData)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load font %s (%s): %w", font.familyName, font.file.name, err)
+	}
+
+	return fontSource, nil
+}
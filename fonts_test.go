@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+)
+
+func TestLoadFontFamilies(t *testing.T) {
+	fontData, err := os.ReadFile(filepath.Join("assets-for-testing", "Roboto-Regular.ttf"))
+	if err != nil {
+		t.Fatalf("Couldn't read the test font: %s", err)
+	}
+
+	newFont := func(familyName string, file FontFile) fontToLoad {
+		return fontToLoad{familyName, components.FontWeightNormal, components.FontStyleNormal, file, fontOptions{sdf: false}}
+	}
+
+	t.Run("Fonts from every kind of source", func(t *testing.T) {
+		fontFamilies, err := loadFontFamilies([]fontToLoad{
+			newFont("Path", FontFromPath(filepath.Join("assets-for-testing", "Roboto-Regular.ttf"))),
+			newFont("FS", FontFromFS(os.DirFS("assets-for-testing"), "Roboto-Regular.ttf")),
+			newFont("Reader", FontFromReader(strings.NewReader(string(fontData)))),
+			newFont("Bytes", FontFromBytes(fontData)),
+		})
+		if err != nil {
+			t.Fatalf("Expected fonts to be loaded, received: %s", err)
+		}
+
+		for _, familyName := range []string{"Path", "FS", "Reader", "Bytes"} {
+			if fontFamilies[familyName] == nil || fontFamilies[familyName].FindVariant(components.FontWeightNormal, false) == nil {
+				t.Errorf("Expected font %s to be loaded", familyName)
+			}
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := loadFontFamilies([]fontToLoad{newFont("Missing", FontFromPath(filepath.Join("assets-for-testing", "Missing.ttf")))})
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist, received: %v", err)
+		}
+	})
+
+	t.Run("Invalid font", func(t *testing.T) {
+		_, err := loadFontFamilies([]fontToLoad{newFont("Invalid", FontFromBytes([]byte("not a font")))})
+
+		if !errors.Is(err, atoms.ErrUnsupportedFontFile) {
+			t.Errorf("Expected atoms.ErrUnsupportedFontFile, received: %v", err)
+		}
+	})
+}
+
+func TestReportMissingCodepoints(t *testing.T) {
+	fontData, err := os.ReadFile(filepath.Join("assets-for-testing", "Roboto-Regular.ttf"))
+	if err != nil {
+		t.Fatalf("Couldn't read the test font: %s", err)
+	}
+
+	fontSource, err := atoms.NewFontSource(fontData)
+	if err != nil {
+		t.Fatalf("Couldn't load the test font: %s", err)
+	}
+
+	key := fontChainKey{"Roboto", components.FontWeightBold, components.FontStyleNormal}
+	fontChain := atoms.NewFontChain(nil, fontSource)
+
+	app := &App{
+		eventBus:                  atoms.NewEventBus(),
+		fontChains:                map[fontChainKey]*atoms.FontChain{key: fontChain},
+		reportedMissingCodepoints: map[fontChainKey]int{},
+	}
+
+	reports := []MissingGlyphsEventArgs{}
+	atoms.Subscribe(app.eventBus, MissingGlyphsEvent, func(args MissingGlyphsEventArgs) {
+		reports = append(reports, args)
+	})
+
+	fontChain.FindSource('a')
+	app.reportMissingCodepoints()
+
+	if len(reports) != 0 {
+		t.Fatalf("Expected no report without missing glyphs, received: %v", reports)
+	}
+
+	fontChain.FindSource('語')
+	fontChain.FindSource('日')
+	app.reportMissingCodepoints()
+	app.reportMissingCodepoints()
+
+	if len(reports) != 1 {
+		t.Fatalf("Expected the missing glyphs to be reported once, received: %v", reports)
+	}
+
+	if reports[0].FontName != "Roboto" || reports[0].FontWeight != components.FontWeightBold || string(reports[0].Codepoints) != "日語" {
+		t.Errorf("Expected the missing glyphs of bold Roboto, received: %v", reports[0])
+	}
+
+	fontChain.FindSource('日')
+	fontChain.FindSource('😀')
+	app.reportMissingCodepoints()
+
+	if len(reports) != 2 || string(reports[1].Codepoints) != "日語😀" {
+		t.Errorf("Expected a new report with the new codepoint, received: %v", reports)
+	}
+}
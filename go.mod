@@ -2,9 +2,10 @@ module domanscy.group/gui
 
 go 1.22.5
 
+require github.com/gen2brain/raylib-go/raylib v0.0.0-20240628125141-62016ee92fc0
+
 require (
 	github.com/ebitengine/purego v0.7.1 // indirect
-	github.com/gen2brain/raylib-go/raylib v0.0.0-20240628125141-62016ee92fc0 // indirect
 	golang.org/x/exp v0.0.0-20240719175910-8a7402abbf56 // indirect
 	golang.org/x/sys v0.22.0 // indirect
 )
@@ -0,0 +1,193 @@
+package gui
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+)
+
+// DefaultDevModePollInterval is how often files are checked for changes, if it isn't set with WithDevMode.
+const DefaultDevModePollInterval = 500 * time.Millisecond
+
+var HotReloadEvent = atoms.NewEvent[HotReloadEventArgs]("gui:hot-reload")
+
+// HotReloadEventArgs are dispatched with gui:hot-reload after a changed file is reloaded in dev mode.
+// Err is set if the new version couldn't be loaded, the app keeps using the previous one then.
+type HotReloadEventArgs struct {
+	Path string
+	Err  error
+}
+
+// markupFile is a markup file rendered into a host layout, see AppBuilder.WithMarkup.
+type markupFile struct {
+	path       string
+	reconciler *components.Reconciler
+}
+
+func newMarkupFile(eventBus *atoms.EventBus, host *components.LayoutComponent, path string) *markupFile {
+	return &markupFile{
+		path:       path,
+		reconciler: components.NewReconciler(eventBus, host),
+	}
+}
+
+// load parses the file and patches the components rendered last time, so unchanged ones keep their state.
+func (markup *markupFile) load() error {
+	element, err := components.LoadMarkupFile(markup.path)
+	if err != nil {
+		return err
+	}
+
+	return markup.reconciler.Update(element)
+}
+
+type fileStamp struct {
+	modificationTime time.Time
+	size             int64
+}
+
+func readFileStamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Editors often replace files, so a missing file is just another state of it.
+		return fileStamp{}
+	}
+
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+// fileWatcher polls files for changes. It doesn't depend on OS notifications, so it works the same way everywhere,
+// also with network drives and editors which replace files instead of writing them.
+type fileWatcher struct {
+	stamps   map[string]fileStamp
+	interval time.Duration
+	onChange func(path string)
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+func newFileWatcher(paths []string, interval time.Duration, onChange func(path string)) *fileWatcher {
+	watcher := &fileWatcher{
+		stamps:   map[string]fileStamp{},
+		interval: interval,
+		onChange: onChange,
+		stopped:  make(chan struct{}),
+	}
+
+	for _, path := range paths {
+		watcher.stamps[path] = readFileStamp(path)
+	}
+
+	return watcher
+}
+
+func (watcher *fileWatcher) start() {
+	go func() {
+		ticker := time.NewTicker(watcher.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watcher.stopped:
+				return
+			case <-ticker.C:
+				for _, path := range watcher.poll() {
+					watcher.onChange(path)
+				}
+			}
+		}
+	}()
+}
+
+// poll returns the files changed since the last poll.
+func (watcher *fileWatcher) poll() []string {
+	changed := []string{}
+
+	for path, stamp := range watcher.stamps {
+		if current := readFileStamp(path); current != stamp {
+			watcher.stamps[path] = current
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
+
+func (watcher *fileWatcher) stop() {
+	watcher.stopOnce.Do(func() {
+		close(watcher.stopped)
+	})
+}
+
+// watchedPaths returns markup files and fonts read from the disk.
+func (app *App) watchedPaths() []string {
+	paths := []string{}
+
+	for _, markup := range app.markups {
+		paths = append(paths, markup.path)
+	}
+
+	for _, font := range app.fontsToLoad {
+		if font.file.path != "" {
+			paths = append(paths, font.file.path)
+		}
+	}
+
+	return paths
+}
+
+// reloadFile runs on the UI thread, the window and the components not described by the file stay as they are.
+func (app *App) reloadFile(path string) {
+	for _, markup := range app.markups {
+		if markup.path == path {
+			atoms.Publish(app.eventBus, HotReloadEvent, HotReloadEventArgs{path, markup.load()})
+		}
+	}
+
+	for _, font := range app.fontsToLoad {
+		if font.file.path == path {
+			atoms.Publish(app.eventBus, HotReloadEvent, HotReloadEventArgs{path, app.reloadFont(font)})
+		}
+	}
+}
+
+func (app *App) reloadFont(font fontToLoad) error {
+	fontSource, err := loadFontSource(font)
+	if err != nil {
+		return err
+	}
+
+	fontFamily := app.fontFamilies[font.familyName]
+	italic := font.style == components.FontStyleItalic
+
+	if previousSource, ok := fontFamily.GetVariant(font.weight, italic); ok {
+		app.glyphCache.UnloadSource(previousSource)
+	}
+
+	fontFamily.AddVariant(font.weight, italic, fontSource)
+
+	// Chains are created again with the new source, and missing glyphs are reported again, the new file may have them.
+	app.fontChains = map[fontChainKey]*atoms.FontChain{}
+	app.reportedMissingCodepoints = map[fontChainKey]int{}
+
+	atoms.Publish(app.eventBus, components.ScheduleRecalculationEvent, struct{}{})
+
+	return nil
+}
+
+// startHotReload watches the files until the returned function is called.
+func (app *App) startHotReload(interval time.Duration) func() {
+	watcher := newFileWatcher(app.watchedPaths(), interval, func(path string) {
+		app.eventBus.InvokeOnUIThread(func() {
+			app.reloadFile(path)
+		})
+	})
+
+	watcher.start()
+
+	return watcher.stop
+}
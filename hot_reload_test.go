@@ -0,0 +1,82 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+)
+
+func TestHotReload(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "screen.xml")
+
+	writeMarkup := func(t *testing.T, markup string) {
+		t.Helper()
+
+		if err := os.WriteFile(path, []byte(markup), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Watcher reports changed files", func(t *testing.T) {
+		writeMarkup(t, `<layout/>`)
+
+		watcher := newFileWatcher([]string{path, filepath.Join(directory, "missing.ttf")}, DefaultDevModePollInterval, nil)
+
+		if changed := watcher.poll(); len(changed) != 0 {
+			t.Errorf("Expected no changes, got %v", changed)
+		}
+
+		writeMarkup(t, `<layout direction="row"/>`)
+
+		if changed := watcher.poll(); !slices.Equal(changed, []string{path}) {
+			t.Errorf("Expected %s to change, got %v", path, changed)
+		}
+
+		if changed := watcher.poll(); len(changed) != 0 {
+			t.Errorf("Expected no more changes, got %v", changed)
+		}
+	})
+
+	t.Run("Reloaded markup keeps unchanged components", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		host, _ := components.NewLayoutComponent(eventBus, components.DirectionColumn, components.AlignStart, components.AlignStart)
+
+		writeMarkup(t, `<layout><text id="title" font="Roboto">Hello</text><rectangle id="card"/></layout>`)
+
+		markup := newMarkupFile(eventBus, host, path)
+		if err := markup.load(); err != nil {
+			t.Fatal(err)
+		}
+
+		title := components.FindByID(host, "title")
+
+		writeMarkup(t, `<layout><text id="title" font="Roboto">Hello again</text></layout>`)
+
+		if err := markup.load(); err != nil {
+			t.Fatal(err)
+		}
+
+		if components.FindByID(host, "title") != title || title.(*components.TextComponent).GetText() != "Hello again" {
+			t.Error("Expected the title to be patched in place")
+		}
+
+		if components.FindByID(host, "card") != nil {
+			t.Error("Expected the card to be removed")
+		}
+
+		writeMarkup(t, `<layout><text id="title">`)
+
+		if err := markup.load(); err == nil {
+			t.Error("Expected an error for invalid markup")
+		}
+
+		if components.FindByID(host, "title") != title {
+			t.Error("Expected invalid markup to keep the previous components")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package gui
+
+import (
+	"slices"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var MouseMovedEvent = atoms.NewEvent[MouseMovedEventArgs]("gui:input:mouse-moved")
+var MouseButtonPressedEvent = atoms.NewEvent[MouseButtonEventArgs]("gui:input:mouse-button-pressed")
+var MouseButtonReleasedEvent = atoms.NewEvent[MouseButtonEventArgs]("gui:input:mouse-button-released")
+var KeyPressedEvent = atoms.NewEvent[KeyEventArgs]("gui:input:key-pressed")
+var KeyReleasedEvent = atoms.NewEvent[KeyEventArgs]("gui:input:key-released")
+var CharTypedEvent = atoms.NewEvent[CharTypedEventArgs]("gui:input:char-typed")
+
+type MouseMovedEventArgs struct {
+	Position rl.Vector2
+}
+
+type MouseButtonEventArgs struct {
+	Button   int32
+	Position rl.Vector2
+}
+
+// KeyEventArgs carry raylib key codes, e.g. rl.KeyEnter.
+type KeyEventArgs struct {
+	Key int32
+}
+
+type CharTypedEventArgs struct {
+	Char rune
+}
+
+var mouseButtons = []int32{
+	rl.MouseButtonLeft,
+	rl.MouseButtonRight,
+	rl.MouseButtonMiddle,
+	rl.MouseButtonSide,
+	rl.MouseButtonExtra,
+	rl.MouseButtonForward,
+	rl.MouseButtonBack,
+}
+
+type inputEvent struct {
+	eventType string
+	payload   interface{}
+}
+
+// frameInput is everything the app loop takes from the outside world in a single frame.
+type frameInput struct {
+	delta      float32
+	windowSize rl.Vector2
+	events     []inputEvent
+}
+
+// inputSource is either the real window (liveInput) or a recording (replayInput).
+type inputSource interface {
+	initialWindowSize() rl.Vector2
+	// nextFrame returns false when there is no more input, e.g. at the end of a replay.
+	nextFrame() (frameInput, bool)
+}
+
+type liveInput struct {
+	mousePosition rl.Vector2
+	pressedKeys   map[int32]bool
+}
+
+func newLiveInput() *liveInput {
+	return &liveInput{
+		mousePosition: rl.Vector2Zero(),
+		pressedKeys:   map[int32]bool{},
+	}
+}
+
+func (input *liveInput) initialWindowSize() rl.Vector2 {
+	return rlGetWindowSize()
+}
+
+func (input *liveInput) nextFrame() (frameInput, bool) {
+	frame := frameInput{
+		delta:      rl.GetFrameTime(),
+		windowSize: rlGetWindowSize(),
+		events:     []inputEvent{},
+	}
+
+	mousePosition := rl.GetMousePosition()
+
+	if !rl.Vector2Equals(mousePosition, input.mousePosition) {
+		input.mousePosition = mousePosition
+		frame.events = append(frame.events, inputEvent{MouseMovedEvent.Name(), MouseMovedEventArgs{mousePosition}})
+	}
+
+	for _, button := range mouseButtons {
+		if rl.IsMouseButtonPressed(button) {
+			frame.events = append(frame.events, inputEvent{MouseButtonPressedEvent.Name(), MouseButtonEventArgs{button, mousePosition}})
+		}
+
+		if rl.IsMouseButtonReleased(button) {
+			frame.events = append(frame.events, inputEvent{MouseButtonReleasedEvent.Name(), MouseButtonEventArgs{button, mousePosition}})
+		}
+	}
+
+	for key := rl.GetKeyPressed(); key != 0; key = rl.GetKeyPressed() {
+		input.pressedKeys[key] = true
+		frame.events = append(frame.events, inputEvent{KeyPressedEvent.Name(), KeyEventArgs{key}})
+	}
+
+	// raylib has no queue of released keys, so the pressed ones are checked one by one.
+	// They are sorted, so a recording of the same input is always the same.
+	pressedKeys := make([]int32, 0, len(input.pressedKeys))
+
+	for key := range input.pressedKeys {
+		pressedKeys = append(pressedKeys, key)
+	}
+
+	slices.Sort(pressedKeys)
+
+	for _, key := range pressedKeys {
+		if rl.IsKeyReleased(key) {
+			delete(input.pressedKeys, key)
+			frame.events = append(frame.events, inputEvent{KeyReleasedEvent.Name(), KeyEventArgs{key}})
+		}
+	}
+
+	for char := rl.GetCharPressed(); char != 0; char = rl.GetCharPressed() {
+		frame.events = append(frame.events, inputEvent{CharTypedEvent.Name(), CharTypedEventArgs{char}})
+	}
+
+	return frame, true
+}
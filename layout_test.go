@@ -2,6 +2,7 @@ package gui
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	. "domanscy.group/gui/components"
@@ -35,7 +36,10 @@ func TestLayoutComponent(t *testing.T) {
 		t.Run(fmt.Sprintf("Subtest %d", i+1), func(t *testing.T) {
 			eventBus := atoms.NewEventBus()
 
-			layout := NewLayoutComponent(eventBus, testCase.direction, testCase.mainAxisAlignment, testCase.crossAxisAlignment)
+			layout, err := NewLayoutComponent(eventBus, testCase.direction, testCase.mainAxisAlignment, testCase.crossAxisAlignment)
+			if err != nil {
+				t.Fatalf("Couldn't create the layout: %s", err)
+			}
 			text1 := NewTextComponent(eventBus, "Hello world", "Roboto", 32, 0, WhiteColor)
 			text2 := NewTextComponent(eventBus, "Mumbo jambo", "Roboto", 32, 0, WhiteColor)
 			text3 := NewTextComponent(eventBus, "DSAOIJDSAOIJSAKJNDSAKJNBDSA", "Roboto", 32, 0, WhiteColor)
@@ -44,10 +48,10 @@ func TestLayoutComponent(t *testing.T) {
 			layout.AddChild(text2)
 			layout.AddChild(text3)
 
-			BuildApp().
+			err = BuildApp().
 				WithTitle("Hello world").
 				WithInitialSize(800, 600).
-				WithFont("Roboto", "assets-for-testing\\Roboto-Regular.ttf").
+				WithFont("Roboto", filepath.Join("assets-for-testing", "Roboto-Regular.ttf")).
 				WithRootElement(layout).
 				WithAppRoutine(func() (stop bool) {
 					stop = true
@@ -55,6 +59,10 @@ func TestLayoutComponent(t *testing.T) {
 				}).
 				Run()
 
+			if err != nil {
+				t.Fatalf("App failed to run: %s", err)
+			}
+
 			text1Pos := text1.GetPosition()
 			text2Pos := text2.GetPosition()
 			text3Pos := text3.GetPosition()
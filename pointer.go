@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// pointerStates turns the mouse input into states of components, so style sheets can target them, e.g. Button:hover.
+// Like in CSS, the component under the cursor and all of its ancestors are hovered, and pressed while the left button is down.
+type pointerStates struct {
+	root          components.Component
+	position      rl.Vector2
+	positionKnown bool
+
+	hovered []components.NodeComponent
+	pressed []components.NodeComponent
+}
+
+func newPointerStates(eventBus *atoms.EventBus, root components.Component) *pointerStates {
+	pointer := &pointerStates{
+		root:          root,
+		position:      rl.Vector2Zero(),
+		positionKnown: false,
+
+		hovered: []components.NodeComponent{},
+		pressed: []components.NodeComponent{},
+	}
+
+	atoms.Subscribe(eventBus, MouseMovedEvent, func(args MouseMovedEventArgs) {
+		pointer.moveTo(args.Position)
+	})
+
+	atoms.Subscribe(eventBus, MouseButtonPressedEvent, func(args MouseButtonEventArgs) {
+		if args.Button == rl.MouseButtonLeft {
+			pointer.moveTo(args.Position)
+			pointer.press()
+		}
+	})
+
+	atoms.Subscribe(eventBus, MouseButtonReleasedEvent, func(args MouseButtonEventArgs) {
+		if args.Button == rl.MouseButtonLeft {
+			pointer.release()
+		}
+	})
+
+	return pointer
+}
+
+func (pointer *pointerStates) moveTo(position rl.Vector2) {
+	pointer.position = position
+	pointer.positionKnown = true
+}
+
+func (pointer *pointerStates) componentsUnderPointer() []components.NodeComponent {
+	nodeComponents := []components.NodeComponent{}
+
+	if !pointer.positionKnown {
+		return nodeComponents
+	}
+
+	for _, component := range components.ComponentsAt(pointer.root, pointer.position) {
+		if nodeComponent, ok := component.(components.NodeComponent); ok {
+			nodeComponents = append(nodeComponents, nodeComponent)
+		}
+	}
+
+	return nodeComponents
+}
+
+func (pointer *pointerStates) press() {
+	pointer.release()
+
+	pointer.pressed = pointer.componentsUnderPointer()
+	setStates(pointer.pressed, components.StatePressed, true)
+}
+
+func (pointer *pointerStates) release() {
+	setStates(pointer.pressed, components.StatePressed, false)
+	pointer.pressed = []components.NodeComponent{}
+}
+
+// update moves the hover to the components under the cursor. It's called every frame, because the components
+// can move under a still cursor, e.g. after a relayout.
+func (pointer *pointerStates) update() {
+	setStates(pointer.hovered, components.StateHover, false)
+
+	pointer.hovered = pointer.componentsUnderPointer()
+	setStates(pointer.hovered, components.StateHover, true)
+}
+
+func setStates(nodeComponents []components.NodeComponent, state string, active bool) {
+	for _, nodeComponent := range nodeComponents {
+		nodeComponent.SetState(state, active)
+	}
+}
@@ -0,0 +1,81 @@
+package gui
+
+import (
+	"testing"
+
+	"domanscy.group/gui/components"
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestPointerStates(t *testing.T) {
+	eventBus := atoms.NewEventBus()
+
+	button, _ := components.NewRectangleComponent(eventBus, nil, rl.Gray, 0)
+	button.SetPaddingRight(100)
+	button.SetPaddingBottom(40)
+
+	root, _ := components.NewLayoutComponent(eventBus, components.DirectionColumn, components.AlignStart, components.AlignStart)
+	root.AddChild(button)
+
+	if _, err := root.CalculateSize(nil, rl.NewVector2(800, 600)); err != nil {
+		t.Fatal(err)
+	}
+
+	root.SetPosition(rl.NewVector2(0, 100))
+
+	sheet, err := components.ParseStyleSheet("Rectangle:hover { backgroundColor: blue } Rectangle:pressed { backgroundColor: darkblue }")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pointer := newPointerStates(eventBus, root)
+
+	frame := func(events ...inputEvent) {
+		for _, event := range events {
+			eventBus.DispatchEvent(event.eventType, event.payload)
+		}
+
+		pointer.update()
+		sheet.Apply(root)
+	}
+
+	frame()
+
+	if button.HasState(components.StateHover) || button.GetBackgroundColor() != rl.Gray {
+		t.Errorf("Expected no hover before the mouse moves, got %v", button.GetBackgroundColor())
+	}
+
+	frame(inputEvent{MouseMovedEvent.Name(), MouseMovedEventArgs{rl.NewVector2(50, 120)}})
+
+	if !button.HasState(components.StateHover) || !root.HasState(components.StateHover) || button.GetBackgroundColor() != rl.Blue {
+		t.Errorf("Expected the button and its parent to be hovered, got %v", button.GetBackgroundColor())
+	}
+
+	frame(inputEvent{MouseButtonPressedEvent.Name(), MouseButtonEventArgs{rl.MouseButtonLeft, rl.NewVector2(50, 120)}})
+
+	if !button.HasState(components.StatePressed) || button.GetBackgroundColor() != rl.DarkBlue {
+		t.Errorf("Expected the button to be pressed, got %v", button.GetBackgroundColor())
+	}
+
+	// The press stays on the component, where it started, until the button is released.
+	frame(inputEvent{MouseMovedEvent.Name(), MouseMovedEventArgs{rl.NewVector2(500, 470)}})
+
+	if button.HasState(components.StateHover) || !button.HasState(components.StatePressed) {
+		t.Error("Expected the button to be pressed, but not hovered")
+	}
+
+	frame(inputEvent{MouseButtonReleasedEvent.Name(), MouseButtonEventArgs{rl.MouseButtonLeft, rl.NewVector2(500, 470)}})
+
+	if button.HasState(components.StatePressed) || button.GetBackgroundColor() != rl.Gray {
+		t.Errorf("Expected the button to be released and unstyled, got %v", button.GetBackgroundColor())
+	}
+
+	// Components moving under a still cursor are hovered too.
+	root.SetPosition(rl.NewVector2(450, 450))
+	frame()
+
+	if !button.HasState(components.StateHover) {
+		t.Error("Expected the moved button to be hovered")
+	}
+}
@@ -0,0 +1,208 @@
+package gui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DefaultReplayFrameDelta is the frame time reported during a replay, if it isn't set with WithEventReplay.
+const DefaultReplayFrameDelta = 1.0 / 60
+
+var ErrEmptyRecording = errors.New("recording has no frames")
+
+type recordedEvent struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// recordedFrame is a single line of a recording file, which is a sequence of JSON objects.
+type recordedFrame struct {
+	Frame      int             `json:"frame"`
+	Time       float64         `json:"time"`
+	Delta      float32         `json:"delta"`
+	WindowSize rl.Vector2      `json:"windowSize"`
+	Events     []recordedEvent `json:"events"`
+}
+
+// eventRecorder writes every event dispatched on the bus, frame by frame. Input events are replayed from the recording,
+// the other ones are kept to compare the replay with the original session.
+type eventRecorder struct {
+	encoder *json.Encoder
+
+	// frameMutex guards the frame, events can be published from any goroutine.
+	frameMutex  sync.Mutex
+	frame       recordedFrame
+	unsubscribe atoms.UnsubscribeFunc
+}
+
+func newEventRecorder(writer io.Writer, eventBus *atoms.EventBus) *eventRecorder {
+	recorder := &eventRecorder{
+		encoder: json.NewEncoder(writer),
+		frame:   recordedFrame{Events: []recordedEvent{}},
+	}
+
+	recorder.unsubscribe = eventBus.Tap(func(eventType string, args ...interface{}) {
+		recorder.frameMutex.Lock()
+		defer recorder.frameMutex.Unlock()
+
+		recorder.frame.Events = append(recorder.frame.Events, recordedEvent{
+			Type:    eventType,
+			Payload: encodeEventPayload(args),
+		})
+	})
+
+	return recorder
+}
+
+func encodeEventPayload(args []interface{}) json.RawMessage {
+	var payload interface{} = args
+
+	if len(args) == 0 {
+		return nil
+	} else if len(args) == 1 {
+		payload = args[0]
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		// Payloads which can't be encoded (e.g. with functions) are recorded only for reading.
+		encoded, _ = json.Marshal(fmt.Sprint(payload))
+	}
+
+	return encoded
+}
+
+// beginFrame starts collecting events of a new frame, events dispatched before it (e.g. during the first layout) go to frame 0.
+func (recorder *eventRecorder) beginFrame(frame int, time float64, input frameInput) {
+	recorder.frameMutex.Lock()
+	defer recorder.frameMutex.Unlock()
+
+	recorder.frame.Frame = frame
+	recorder.frame.Time = time
+	recorder.frame.Delta = input.delta
+	recorder.frame.WindowSize = input.windowSize
+}
+
+func (recorder *eventRecorder) endFrame() error {
+	recorder.frameMutex.Lock()
+	defer recorder.frameMutex.Unlock()
+
+	err := recorder.encoder.Encode(recorder.frame)
+
+	recorder.frame.Events = []recordedEvent{}
+
+	return err
+}
+
+func (recorder *eventRecorder) close() {
+	recorder.unsubscribe()
+}
+
+// replayInput feeds the input of a recording back to the app loop, with a fixed frame delta and the recorded window sizes,
+// whatever the size of the real window is.
+type replayInput struct {
+	frames     []recordedFrame
+	nextIndex  int
+	frameDelta float32
+}
+
+func readRecording(reader io.Reader, frameDelta float32) (*replayInput, error) {
+	frames := []recordedFrame{}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var frame recordedFrame
+
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid recording frame at line %d: %w", line, err)
+		}
+
+		frames = append(frames, frame)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(frames) == 0 {
+		return nil, ErrEmptyRecording
+	}
+
+	return &replayInput{
+		frames:     frames,
+		nextIndex:  0,
+		frameDelta: frameDelta,
+	}, nil
+}
+
+func (input *replayInput) initialWindowSize() rl.Vector2 {
+	return input.frames[0].WindowSize
+}
+
+func (input *replayInput) nextFrame() (frameInput, bool) {
+	// Frame 0 holds only events of the first layout, which happens before the loop.
+	for input.nextIndex < len(input.frames) && input.frames[input.nextIndex].Frame == 0 {
+		input.nextIndex++
+	}
+
+	if input.nextIndex >= len(input.frames) {
+		return frameInput{}, false
+	}
+
+	recorded := input.frames[input.nextIndex]
+	input.nextIndex++
+
+	frame := frameInput{
+		delta:      input.frameDelta,
+		windowSize: recorded.WindowSize,
+		events:     []inputEvent{},
+	}
+
+	for _, event := range recorded.Events {
+		if payload, ok := decodeInputEvent(event); ok {
+			frame.events = append(frame.events, inputEvent{event.Type, payload})
+		}
+	}
+
+	return frame, true
+}
+
+// decodeInputEvent decodes payloads of input events, other events are produced again by the replayed app.
+func decodeInputEvent(event recordedEvent) (interface{}, bool) {
+	switch event.Type {
+	case MouseMovedEvent.Name():
+		return decodePayload[MouseMovedEventArgs](event.Payload)
+	case MouseButtonPressedEvent.Name(), MouseButtonReleasedEvent.Name():
+		return decodePayload[MouseButtonEventArgs](event.Payload)
+	case KeyPressedEvent.Name(), KeyReleasedEvent.Name():
+		return decodePayload[KeyEventArgs](event.Payload)
+	case CharTypedEvent.Name():
+		return decodePayload[CharTypedEventArgs](event.Payload)
+	}
+
+	return nil, false
+}
+
+func decodePayload[T any](encoded json.RawMessage) (interface{}, bool) {
+	var payload T
+
+	if err := json.Unmarshal(encoded, &payload); err != nil {
+		return nil, false
+	}
+
+	return payload, true
+}
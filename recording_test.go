@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"domanscy.group/gui/components/atoms"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func TestEventRecording(t *testing.T) {
+	t.Run("Recorded input is replayed with a fixed frame delta", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		output := bytes.Buffer{}
+
+		recorder := newEventRecorder(&output, eventBus)
+
+		recorder.beginFrame(0, 0, frameInput{windowSize: rl.NewVector2(800, 600)})
+		atoms.Publish(eventBus, MissingGlyphsEvent, MissingGlyphsEventArgs{FontName: "Roboto", Codepoints: []rune{'x'}})
+
+		if err := recorder.endFrame(); err != nil {
+			t.Fatal(err)
+		}
+
+		recorder.beginFrame(1, 0.02, frameInput{delta: 0.02, windowSize: rl.NewVector2(800, 600)})
+		atoms.Publish(eventBus, MouseMovedEvent, MouseMovedEventArgs{rl.NewVector2(10, 20)})
+		atoms.Publish(eventBus, KeyPressedEvent, KeyEventArgs{rl.KeyEnter})
+		atoms.Publish(eventBus, FrameEvent, FrameEventArgs{Frame: 1, Delta: 0.02, Time: 0.02})
+
+		if err := recorder.endFrame(); err != nil {
+			t.Fatal(err)
+		}
+
+		recorder.beginFrame(2, 0.05, frameInput{delta: 0.03, windowSize: rl.NewVector2(1024, 768)})
+		atoms.Publish(eventBus, CharTypedEvent, CharTypedEventArgs{'ż'})
+
+		if err := recorder.endFrame(); err != nil {
+			t.Fatal(err)
+		}
+
+		recorder.close()
+		atoms.Publish(eventBus, CharTypedEvent, CharTypedEventArgs{'a'})
+
+		replay, err := readRecording(&output, 0.5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if replay.initialWindowSize() != rl.NewVector2(800, 600) {
+			t.Errorf("Expected initial window size 800x600, got %v", replay.initialWindowSize())
+		}
+
+		first, ok := replay.nextFrame()
+		if !ok {
+			t.Fatal("Expected the first frame")
+		}
+
+		if first.delta != 0.5 {
+			t.Errorf("Expected frame delta 0.5, got %f", first.delta)
+		}
+
+		expectedEvents := []inputEvent{
+			{MouseMovedEvent.Name(), MouseMovedEventArgs{rl.NewVector2(10, 20)}},
+			{KeyPressedEvent.Name(), KeyEventArgs{rl.KeyEnter}},
+		}
+
+		if len(first.events) != len(expectedEvents) {
+			t.Fatalf("Expected %d input events, got %v", len(expectedEvents), first.events)
+		}
+
+		for i, event := range first.events {
+			if event != expectedEvents[i] {
+				t.Errorf("Expected event %v, got %v", expectedEvents[i], event)
+			}
+		}
+
+		second, ok := replay.nextFrame()
+		if !ok {
+			t.Fatal("Expected the second frame")
+		}
+
+		if second.windowSize != rl.NewVector2(1024, 768) {
+			t.Errorf("Expected window size 1024x768, got %v", second.windowSize)
+		}
+
+		if len(second.events) != 1 || second.events[0].payload != (CharTypedEventArgs{'ż'}) {
+			t.Errorf("Expected a single typed char, got %v", second.events)
+		}
+
+		if _, ok := replay.nextFrame(); ok {
+			t.Error("Expected the replay to end after the last recorded frame")
+		}
+	})
+
+	t.Run("Events published from other goroutines are recorded", func(t *testing.T) {
+		eventBus := atoms.NewEventBus()
+		output := bytes.Buffer{}
+
+		recorder := newEventRecorder(&output, eventBus)
+		defer recorder.close()
+
+		published := sync.WaitGroup{}
+
+		for i := 0; i < 10; i++ {
+			published.Add(1)
+
+			go func() {
+				defer published.Done()
+				atoms.Publish(eventBus, CharTypedEvent, CharTypedEventArgs{'a'})
+			}()
+		}
+
+		recorder.beginFrame(1, 0, frameInput{windowSize: rl.NewVector2(800, 600)})
+		published.Wait()
+
+		if err := recorder.endFrame(); err != nil {
+			t.Fatal(err)
+		}
+
+		replay, err := readRecording(&output, DefaultReplayFrameDelta)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if frame, _ := replay.nextFrame(); len(frame.events) != 10 {
+			t.Errorf("Expected 10 recorded events, got %v", frame.events)
+		}
+	})
+
+	t.Run("Invalid recordings are reported", func(t *testing.T) {
+		_, err := readRecording(strings.NewReader("\n\n"), DefaultReplayFrameDelta)
+		if !errors.Is(err, ErrEmptyRecording) {
+			t.Errorf("Expected ErrEmptyRecording, got %v", err)
+		}
+
+		_, err = readRecording(strings.NewReader("{\"frame\": 0}\n{\"frame\": 1,"), DefaultReplayFrameDelta)
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Expected an error at line 2, got %v", err)
+		}
+	})
+}